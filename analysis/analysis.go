@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"fmt"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/replay"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"hash/fnv"
+	"math/rand"
+)
+
+// Samples is the number of fleet layouts drawn to estimate the hit chances
+// before every shot.
+const Samples = 1000
+
+// a shot with less than this fraction of the best chance is a mistake
+const mistakeRatio = 0.5
+
+type Shot struct {
+	Number int
+	Coord  string
+	Result string
+	// estimated chance that the chosen cell held a ship
+	Chance float64
+	// the cell with the highest chance and that chance
+	Best       string
+	BestChance float64
+	// the cell the strategy would have fired at
+	Recommended string
+	Blunder     bool
+	Mistake     bool
+	Note        string
+}
+
+type Report struct {
+	Nick     string
+	Opponent string
+	Status   string
+	Strategy string
+	Seed     int64
+	Shots    []Shot
+	Hits     int
+	Blunders int
+	Mistakes int
+	// sums of the chances, divided by the number of shots they are the
+	// expected accuracy of our shots and of always firing at the best cell
+	ChanceSum     float64
+	BestChanceSum float64
+}
+
+// Analyze goes through our shots in g and compares each of them with the
+// best cell according to the hit chances and with the choice of the named
+// strategy. The same seed always gives the same report.
+func Analyze(g *replay.Game, strategyName string, seed int64) (*Report, error) {
+	r := rand.New(rand.NewSource(seed))
+	s, err := strategy.New(strategyName, rand.New(rand.NewSource(r.Int63())))
+	if err != nil {
+		return nil, fmt.Errorf("strategy.New: %w", err)
+	}
+
+	rep := &Report{
+		Nick:     g.Start.Nick,
+		Opponent: g.Start.Opponent,
+		Strategy: strategyName,
+		Seed:     seed,
+	}
+	if g.End != nil {
+		rep.Status = g.End.Status
+	}
+
+	var shots []strategy.Shot
+	for i, move := range g.Moves {
+		if move.Type != replay.TypeShot {
+			continue
+		}
+		f := g.Frame(i)
+		state := strategy.State{Board: f.Opponent, Fleet: f.Fleet, Shots: shots}
+		p, _ := board.ParsePoint(move.Coord)
+
+		shot := analyzeShot(state, p, r)
+		shot.Number = len(rep.Shots) + 1
+		shot.Coord = move.Coord
+		shot.Result = move.Result
+		shot.Recommended = strategy.Decide(s, state).Point.String()
+
+		rep.Shots = append(rep.Shots, shot)
+		rep.ChanceSum += shot.Chance
+		rep.BestChanceSum += shot.BestChance
+		if move.Result != "miss" {
+			rep.Hits++
+		}
+		if shot.Blunder {
+			rep.Blunders++
+		}
+		if shot.Mistake {
+			rep.Mistakes++
+		}
+		shots = append(shots, strategy.Shot{Point: p, Result: move.Result})
+	}
+	return rep, nil
+}
+
+// Seed derives a seed from the recorded game, so that the same replay always
+// gets the same report.
+func Seed(g *replay.Game) int64 {
+	h := fnv.New64a()
+	fmt.Fprintln(h, g.Start.Nick, g.Start.Opponent, g.Start.Board)
+	for _, m := range g.Moves {
+		fmt.Fprintln(h, m.Type, m.Coord, m.Result)
+	}
+	return int64(h.Sum64() >> 1)
+}
+
+func analyzeShot(state strategy.State, p board.Point, r *rand.Rand) Shot {
+	var shot Shot
+	probs, n := strategy.HitProbabilities(state, Samples, r)
+	if n > 0 {
+		best := p
+		for _, c := range state.Board.Find(board.Unknown) {
+			if probs[c.X][c.Y] > probs[best.X][best.Y] {
+				best = c
+			}
+		}
+		shot.Chance = probs[p.X][p.Y]
+		shot.Best = best.String()
+		shot.BestChance = probs[best.X][best.Y]
+	}
+
+	switch cell := state.Board.At(p); {
+	case cell == board.Excluded:
+		shot.Blunder = true
+		shot.Note = "the cell touches a sunk ship, the no-touch rule excludes it"
+	case cell != board.Unknown:
+		shot.Blunder = true
+		shot.Note = fmt.Sprintf("the cell was already shot (%s)", cell)
+	case n > 0 && shot.Chance == 0:
+		shot.Blunder = true
+		shot.Note = "no remaining ship fits on this cell"
+	case ignoresOpenHit(state.Board, p):
+		shot.Mistake = true
+		shot.Note = "a damaged ship was left to fire elsewhere"
+	case n > 0 && shot.Chance < mistakeRatio*shot.BestChance:
+		shot.Mistake = true
+		shot.Note = fmt.Sprintf("%s was more than twice as likely to hit", shot.Best)
+	}
+	return shot
+}
+
+func ignoresOpenHit(b board.Board, p board.Point) bool {
+	open := b.Find(board.Hit)
+	if len(open) == 0 {
+		return false
+	}
+	for _, h := range open {
+		for _, n := range h.Neighbours() {
+			if n == p {
+				return false
+			}
+		}
+	}
+	return true
+}
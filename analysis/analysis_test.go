@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"bytes"
+	"github.com/wojtekolesinski/battleships/replay"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const recorded = `{"type":"start","version":1,"nick":"me","opponent":"them","board":["A1","B1","C1","D1","F1","G1","H1","A3","A4","A5","C3","D3","J1","J2","F3","F4","H3","C6","E8","J9"]}
+{"type":"shot","coord":"E5","result":"miss"}
+{"type":"opp_shot","coord":"A1","result":"hit"}
+{"type":"opp_shot","coord":"J10","result":"miss"}
+{"type":"shot","coord":"A1","result":"sunk"}
+{"type":"shot","coord":"B2","result":"miss"}
+{"type":"opp_shot","coord":"B1","result":"hit"}
+{"type":"opp_shot","coord":"B5","result":"miss"}
+{"type":"shot","coord":"E5","result":"miss"}
+{"type":"opp_shot","coord":"C5","result":"miss"}
+{"type":"shot","coord":"H8","result":"hit"}
+{"type":"shot","coord":"A10","result":"miss"}
+{"type":"opp_shot","coord":"D5","result":"miss"}
+{"type":"shot","coord":"H9","result":"hit"}
+{"type":"end","status":"lose"}
+`
+
+func load(t *testing.T, data string) *replay.Game {
+	t.Helper()
+	g, err := replay.Read(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("replay.Read: %v", err)
+	}
+	return g
+}
+
+func TestAnalyze(t *testing.T) {
+	g := load(t, recorded)
+	rep, err := Analyze(g, "hunt-target", Seed(g))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(rep.Shots) != 7 || rep.Hits != 3 || rep.Status != "lose" {
+		t.Fatalf("report of %d shots, %d hits, status %q, want 7 shots, 3 hits, lose", len(rep.Shots), rep.Hits, rep.Status)
+	}
+
+	tests := []struct {
+		number  int
+		verdict string
+		note    string
+	}{
+		{number: 3, verdict: "blunder", note: "the no-touch rule excludes it"},
+		{number: 4, verdict: "blunder", note: "the cell was already shot (water)"},
+		{number: 6, verdict: "mistake", note: "a damaged ship was left to fire elsewhere"},
+		{number: 7, verdict: ""},
+	}
+	for _, tt := range tests {
+		shot := rep.Shots[tt.number-1]
+		if shot.Verdict() != tt.verdict || !strings.Contains(shot.Note, tt.note) {
+			t.Errorf("shot %d at %s: %q (%s), want %q (%s)", tt.number, shot.Coord, shot.Verdict(), shot.Note, tt.verdict, tt.note)
+		}
+	}
+	if rep.Blunders < 2 || rep.Mistakes < 1 {
+		t.Errorf("%d blunders and %d mistakes, want at least 2 and 1", rep.Blunders, rep.Mistakes)
+	}
+
+	// the hit at H8 is open, so the strategy finishes the ship next to it
+	if rec := rep.Shots[5].Recommended; rec != "H9" && rec != "G8" && rec != "I8" && rec != "H7" {
+		t.Errorf("recommended %s with an open hit at H8", rec)
+	}
+	for _, shot := range rep.Shots {
+		if shot.Chance > shot.BestChance {
+			t.Errorf("shot %d has a chance of %f above the best %f", shot.Number, shot.Chance, shot.BestChance)
+		}
+	}
+}
+
+func TestSameReplaySameReport(t *testing.T) {
+	for _, name := range []string{"hunt-target", "monte-carlo"} {
+		t.Run(name, func(t *testing.T) {
+			first, err := Analyze(load(t, recorded), name, Seed(load(t, recorded)))
+			if err != nil {
+				t.Fatalf("Analyze: %v", err)
+			}
+			second, err := Analyze(load(t, recorded), name, Seed(load(t, recorded)))
+			if err != nil {
+				t.Fatalf("Analyze: %v", err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("the same replay gave different reports:\n%+v\n%+v", first, second)
+			}
+		})
+	}
+
+	other := load(t, strings.Replace(recorded, `"coord":"A10"`, `"coord":"B10"`, 1))
+	if Seed(other) == Seed(load(t, recorded)) {
+		t.Error("different replays got the same seed")
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"report.md", FormatMarkdown},
+		{"report.MARKDOWN", FormatMarkdown},
+		{"report.html", FormatHTML},
+		{"report.htm", FormatHTML},
+		{"report.txt", FormatText},
+		{"report", FormatText},
+	}
+	for _, tt := range tests {
+		if got := FormatFor(tt.path); got != tt.want {
+			t.Errorf("FormatFor(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := load(t, recorded)
+	rep, err := Analyze(g, "hunt-target", 1)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatText, []string{"me vs them (lose)\n", "Blunders: ", "seed 1", "the cell was already shot"}},
+		{FormatMarkdown, []string{"# me vs them (lose)\n", "| 3 | B2 | miss |", "**blunder**: "}},
+		{FormatHTML, []string{"<title>me vs them (lose)</title>", `<tr class="blunder">`, `<tr class="mistake">`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := rep.Write(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("the %s report does not contain %q:\n%s", tt.format, want, buf.String())
+				}
+			}
+		})
+	}
+
+	if err := rep.Write(&bytes.Buffer{}, "pdf"); err == nil {
+		t.Error("Write accepted an unknown format")
+	}
+}
@@ -0,0 +1,150 @@
+package analysis
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+func FormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".html", ".htm":
+		return FormatHTML
+	}
+	return FormatText
+}
+
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		return r.writeText(w)
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	case FormatHTML:
+		return htmlReport.Execute(w, r)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func (r *Report) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create: %w", err)
+	}
+	defer f.Close()
+
+	err = r.Write(f, FormatFor(path))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (r *Report) Title() string {
+	title := fmt.Sprintf("%s vs %s", r.Nick, r.Opponent)
+	if r.Status != "" {
+		title += fmt.Sprintf(" (%s)", r.Status)
+	}
+	return title
+}
+
+func (r *Report) Summary() []string {
+	n := float64(len(r.Shots))
+	if n == 0 {
+		return []string{"No shots were fired"}
+	}
+	return []string{
+		fmt.Sprintf("Shots: %d, hits: %d, accuracy %.1f%%", len(r.Shots), r.Hits, 100*float64(r.Hits)/n),
+		fmt.Sprintf("Average hit chance of the chosen cells: %.1f%%, of the best cells: %.1f%%", 100*r.ChanceSum/n, 100*r.BestChanceSum/n),
+		fmt.Sprintf("Blunders: %d, mistakes: %d", r.Blunders, r.Mistakes),
+		fmt.Sprintf("Recommendations by the %s strategy, seed %d", r.Strategy, r.Seed),
+	}
+}
+
+func (s Shot) Verdict() string {
+	switch {
+	case s.Blunder:
+		return "blunder"
+	case s.Mistake:
+		return "mistake"
+	}
+	return ""
+}
+
+func (r *Report) writeText(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\n", r.Title())
+	for _, line := range r.Summary() {
+		fmt.Fprintf(&sb, "%s\n", line)
+	}
+	fmt.Fprintf(&sb, "\n%4s  %-5s %-6s %7s  %-5s %7s  %-11s  %s\n", "#", "SHOT", "RESULT", "CHANCE", "BEST", "CHANCE", "RECOMMENDED", "VERDICT")
+	for _, s := range r.Shots {
+		fmt.Fprintf(&sb, "%4d  %-5s %-6s %6.1f%%  %-5s %6.1f%%  %-11s  %s\n",
+			s.Number, s.Coord, s.Result, 100*s.Chance, s.Best, 100*s.BestChance, s.Recommended, s.Verdict())
+		if s.Note != "" {
+			fmt.Fprintf(&sb, "      %s\n", s.Note)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", r.Title())
+	for _, line := range r.Summary() {
+		fmt.Fprintf(&sb, "- %s\n", line)
+	}
+	sb.WriteString("\n| # | Shot | Result | Chance | Best | Best chance | Recommended | Verdict |\n")
+	sb.WriteString("|---:|---|---|---:|---|---:|---|---|\n")
+	for _, s := range r.Shots {
+		verdict := s.Verdict()
+		if s.Note != "" {
+			verdict = fmt.Sprintf("**%s**: %s", verdict, s.Note)
+		}
+		fmt.Fprintf(&sb, "| %d | %s | %s | %.1f%% | %s | %.1f%% | %s | %s |\n",
+			s.Number, s.Coord, s.Result, 100*s.Chance, s.Best, 100*s.BestChance, s.Recommended, verdict)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.1f%%", 100*f) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 2px 8px; }
+td.num { text-align: right; }
+tr.blunder { background: #f8c8c8; }
+tr.mistake { background: #f8ecc0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+{{range .Summary}}<li>{{.}}</li>
+{{end}}</ul>
+<table>
+<tr><th>#</th><th>Shot</th><th>Result</th><th>Chance</th><th>Best</th><th>Best chance</th><th>Recommended</th><th>Verdict</th></tr>
+{{range .Shots}}<tr class="{{.Verdict}}"><td class="num">{{.Number}}</td><td>{{.Coord}}</td><td>{{.Result}}</td><td class="num">{{percent .Chance}}</td><td>{{.Best}}</td><td class="num">{{percent .BestChance}}</td><td>{{.Recommended}}</td><td>{{.Verdict}}{{if .Note}}: {{.Note}}{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"github.com/wojtekolesinski/battleships/analysis"
+	"github.com/wojtekolesinski/battleships/replay"
+	"os"
+)
+
+// AnalyzeReplay compares our shots in a recorded game with the best ones.
+// The report is printed when out is empty, otherwise saved to out as text,
+// Markdown or HTML depending on the extension.
+func (a *App) AnalyzeReplay(path, out string) error {
+	g, err := replay.Load(path)
+	if err != nil {
+		return fmt.Errorf("replay.Load: %w", err)
+	}
+
+	name := a.strategyName
+	if name == "" {
+		name = "monte-carlo"
+	}
+	fmt.Println("Analyzing the game...")
+	report, err := analysis.Analyze(g, name, analysis.Seed(g))
+	if err != nil {
+		return fmt.Errorf("analysis.Analyze: %w", err)
+	}
+
+	if out == "" {
+		fmt.Println()
+		return report.Write(os.Stdout, analysis.FormatText)
+	}
+	err = report.SaveFile(out)
+	if err != nil {
+		return fmt.Errorf("analysis.Report.SaveFile: %w", err)
+	}
+	fmt.Printf("The report was saved to %s\n", out)
+	return nil
+}
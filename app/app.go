@@ -5,100 +5,221 @@ import (
 	"errors"
 	"fmt"
 	"github.com/charmbracelet/log"
-	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
 	"github.com/wojtekolesinski/battleships/client"
 	"github.com/wojtekolesinski/battleships/models"
+	"github.com/wojtekolesinski/battleships/replay"
+	"github.com/wojtekolesinski/battleships/retry"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"math/rand"
+	"os"
 	"strings"
 	"time"
 )
 
+const networkSafetyMargin = 3 * time.Second
+
 var ErrorGameEnded = fmt.Errorf("game ended")
-var maxRequests = 3
+var retryPolicy = retry.DefaultPolicy()
+
+func SetRetryPolicy(p retry.Policy) {
+	retryPolicy = p
+}
+
+type GameServer interface {
+	InitGame(ctx context.Context, payload models.GamePayload) error
+	GetStatus(ctx context.Context) (models.StatusData, error)
+	GetBoard(ctx context.Context) (models.Board, error)
+	GetDescription(ctx context.Context) (models.StatusData, error)
+	Fire(ctx context.Context, coord string) (models.FireAnswer, error)
+	GetPlayersList(ctx context.Context) ([]models.ListData, error)
+	RefreshSession(ctx context.Context) error
+	GetStats(ctx context.Context) (models.StatsList, error)
+	GetPlayerStats(ctx context.Context, nick string) (models.StatsNick, error)
+	AbandonGame(ctx context.Context) error
+	// the session token, saved to resume an interrupted game
+	Token() string
+	SetToken(token string)
+}
+
+var _ GameServer = (*client.Client)(nil)
 
 type App struct {
-	client        *client.Client
-	playerBoard   Board
-	opponentBoard Board
-	status        models.StatusData
-	totalShots    int
-	hits          int
-	ui            *ui
-	customBoard   []string
-	oppFleet      map[int]int
-	bot           *bot
-	useAssistant  bool
-	useBot        bool
+	client          GameServer
+	playerBoard     board.Board
+	opponentBoard   board.Board
+	status          models.StatusData
+	totalShots      int
+	hits            int
+	ui              gameUi
+	textMode        bool
+	unattended      bool
+	playerSet       bool
+	customBoard     []string
+	oppFleet        board.Fleet
+	pick            *board.Point
+	strategy        strategy.Strategy
+	strategyName    string
+	currentStrategy string
+	useAssistant    bool
+	useBot          bool
+	shots           []savedShot
+	sessionPath     string
+	libraryPath     string
+	boardName       string
+	gameBoard       string
+	replayDir       string
+	recorder        *replay.Recorder
+	oppShotsSeen    int
+	inferred        bool
+	inferredBoard   board.Board
+	resume          *savedSession
 }
 
-func New(c *client.Client) *App {
+func New(c GameServer) *App {
 	return &App{
-		client: c,
+		client:      c,
+		sessionPath: configPath("session.json"),
+		libraryPath: configPath("library.json"),
+		replayDir:   configPath("replays"),
 	}
 }
 
-func (a *App) Run() error {
-	a.getNameAndDescription()
+// SetTextMode switches the game to a line-based front end that prints the
+// boards and reads targets from the standard input.
+func (a *App) SetTextMode(on bool) {
+	a.textMode = on
+}
+
+// SetPlayer sets the nick and description sent to the server, so that the
+// player is not asked for them. Empty values are assigned by the server.
+func (a *App) SetPlayer(nick, desc string) {
+	a.status.Nick = nick
+	a.status.Desc = desc
+	a.playerSet = true
+}
+
+func (a *App) Run(ctx context.Context) error {
+	if !a.playerSet {
+		err := a.getNameAndDescription()
+		if err != nil {
+			return fmt.Errorf("app.getNameAndDescription: %w", err)
+		}
+	}
+	a.useDefaultBoard()
 
 	for {
-		gamePayload, err := a.displayMenu()
+		gamePayload, err := a.displayMenu(ctx)
 		if err != nil {
 			return fmt.Errorf("app.displayMenu: %w", err)
 		}
 
-		err = a.initGame(gamePayload)
+		if a.resume != nil {
+			err = a.resumeGame(ctx, a.resume)
+			a.resume = nil
+		} else {
+			err = a.initGame(ctx, gamePayload)
+		}
+		if errors.Is(err, errCannotResume) {
+			log.Warn("app [Run]", "err", err)
+			fmt.Printf("\nThe %s\n\n", err)
+			continue
+		}
+		var invalid *board.ValidationError
+		if errors.As(err, &invalid) {
+			log.Warn("app [Run] - invalid board", "err", err)
+			fmt.Println("\nYour board is invalid:")
+			for _, v := range invalid.Violations {
+				fmt.Printf(" - %s\n", v.Message)
+			}
+			fmt.Println()
+			continue
+		}
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+			log.Warn("app [Run] - game rejected", "err", err)
+			fmt.Printf("\nThe server rejected the game: %s\n\n", apiErr.Reason())
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("app.initGame: %w", err)
 		}
 
-		errChan := make(chan error, 0)
-		ctx, cancelFunc := context.WithCancel(context.Background())
-		go a.loop(ctx, errChan, cancelFunc)
-		go func() {
-			for {
-				select {
-				case err = <-errChan:
-					cancelFunc()
-					errChan <- err
-				default:
-					time.Sleep(500 * time.Millisecond)
-				}
-			}
-		}()
-
-		log.Info("app [Run] - Starting ui")
-		a.ui.gui.Start(ctx, nil)
-		err = a.updateStatus()
+		err = a.runGame(ctx)
 		if err != nil {
-			return fmt.Errorf("app.updateStatus: %w", err)
+			return err
 		}
+	}
+}
 
-		if a.gameInProgress() {
-			log.Info("app [Run] - abandoning game")
-			makeRequest(func() error {
-				err = a.client.AbandonGame()
-				return err
-			})
-			if err != nil {
-				return fmt.Errorf("client.AbandonGame: %w", err)
-			}
+// Play plays a single game without asking any questions, the bot fires for
+// the player and the boards are printed as text. The opponent is either a
+// nick, "wp_bot", or empty to wait for an invitation. It returns the result
+// of the game as reported by the server.
+func (a *App) Play(ctx context.Context, opponent string) (string, error) {
+	a.unattended = true
+	a.textMode = true
+	a.useDefaultBoard()
+
+	err := a.initGame(ctx, a.getGamePayload(opponent))
+	if err != nil {
+		return "", fmt.Errorf("app.initGame: %w", err)
+	}
+	err = a.runGame(ctx)
+	if err != nil {
+		return "", err
+	}
+	return a.status.LastGameStatus, nil
+}
+
+func (a *App) runGame(ctx context.Context) error {
+	errChan := make(chan error, 1)
+	done := make(chan struct{})
+	gameCtx, cancelFunc := context.WithCancel(ctx)
+	go func() {
+		defer close(done)
+		a.loop(gameCtx, errChan, cancelFunc)
+	}()
+
+	log.Info("app [runGame] - Starting ui")
+	a.ui.start(gameCtx)
+	cancelFunc()
+	<-done
+
+	err := a.updateStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("app.updateStatus: %w", err)
+	}
+
+	if a.gameInProgress() {
+		log.Info("app [runGame] - abandoning game")
+		err = makeRequest(ctx, func() error {
+			err = a.client.AbandonGame(ctx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("client.AbandonGame: %w", err)
 		}
+		a.clearSession()
+		a.stopRecording("abandoned")
+	}
+	a.stopRecording(a.status.LastGameStatus)
 
-		select {
-		case err = <-errChan:
-			if !errors.Is(err, ErrorGameEnded) {
-				return err
-			}
-		default:
-			continue
+	select {
+	case err = <-errChan:
+		if !errors.Is(err, ErrorGameEnded) && !errors.Is(err, context.Canceled) {
+			return err
 		}
+	default:
 	}
+	return nil
 }
 
 func (a *App) loop(ctx context.Context, errChan chan error, cancelFunc context.CancelFunc) {
 	log.Info("app [Run] - starting gameloop", "status", a.status)
 	defer cancelFunc()
 	for a.gameInProgress() {
-		err := a.waitForYourTurn()
+		err := a.waitForYourTurn(ctx)
 		if err != nil {
 			if errors.Is(err, ErrorGameEnded) {
 				log.Info("app [Run] - game ended")
@@ -114,30 +235,45 @@ func (a *App) loop(ctx context.Context, errChan chan error, cancelFunc context.C
 			return
 		}
 
-		time.Sleep(1 * time.Second)
-		err = a.updateStatus()
+		err = sleep(ctx, time.Second)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		err = a.updateStatus(ctx)
 		if err != nil {
 			errChan <- fmt.Errorf("app.updateStatus: %w", err)
 			return
 		}
 	}
 	log.Info("app [Run] - exited gameloop")
+	a.clearSession()
 	a.updateOppShots()
+	a.stopRecording(a.status.LastGameStatus)
+	a.recordBoardResult()
 	a.updateBoard()
 	a.ui.renderGameResult(a.status.LastGameStatus)
+	if a.unattended {
+		return
+	}
 	for i := 5; i > 0; i-- {
 		a.ui.setExitText(fmt.Sprintf("Exiting in %ds", i))
-		time.Sleep(1 * time.Second)
+		if sleep(ctx, time.Second) != nil {
+			return
+		}
 	}
 }
 
-func (a *App) waitForYourTurn() error {
+func (a *App) waitForYourTurn(ctx context.Context) error {
 	log.Info("app [waitForYourTurn] - starting to wait")
 	a.ui.setInfoText("Opponent's turn")
 
 	for !a.status.ShouldFire {
-		time.Sleep(2 * time.Second)
-		err := a.updateStatus()
+		err := sleep(ctx, 2*time.Second)
+		if err != nil {
+			return err
+		}
+		err = a.updateStatus(ctx)
 		if err != nil {
 			return fmt.Errorf("app.updateStatus: %w", err)
 		}
@@ -159,6 +295,7 @@ func (a *App) waitForYourTurn() error {
 
 func (a *App) handleShot(ctx context.Context) (string, error) {
 	log.Debug("app [handleShot]", "status", a.status)
+	deadline := time.Now().Add(time.Duration(a.status.Timer)*time.Second - networkSafetyMargin)
 	a.ui.updateTime(a.status.Timer)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -167,42 +304,63 @@ func (a *App) handleShot(ctx context.Context) (string, error) {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				time.Sleep(time.Second)
+			case <-time.After(time.Second):
 				a.status.Timer--
 				a.ui.updateTime(a.status.Timer)
 			}
 		}
 	}()
-	rec := a.bot.getRecommendation(a.opponentBoard, a.oppFleet)
+	state := a.strategyState()
+	state.Deadline = deadline
+	a.ui.setThinking(true)
+	rec := strategy.Decide(a.strategy, state)
+	a.ui.setThinking(false)
+	log.Debug("app [handleShot]", "recommendation", rec.Point, "mode", rec.Mode, "score", rec.Score, "timeLeft", time.Until(deadline))
 	if a.useBot {
-		return fmt.Sprintf("%c%d", rec.x+'A', rec.y+1), nil
+		return rec.Point.String(), nil
 	}
 
 	a.ui.setInfoText("Choose your target:")
 	if a.useAssistant {
-		a.opponentBoard[rec.x][rec.y] = gui.Ship
+		log.Info("app [handleShot] - assistant's pick", "point", rec.Point, "reasons", strings.Join(rec.Lines(), "; "))
+		a.pick = &rec.Point
+		a.ui.setHeatmap(newHeatmap(state))
+		a.ui.setExplanation(rec.Lines())
 		a.updateBoard()
 	}
 	for {
-		coords := a.ui.board2.Listen(ctx)
-		x, y, err := parseCoords(coords)
+		coords, err := a.ui.listenTarget(ctx)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		if err != nil {
-			return "", fmt.Errorf("parseCoords: %w", err)
+			// with the input closed no target will ever come, the game is
+			// abandoned once the loop ends
+			log.Warn("app [handleShot] - no more targets", "err", err)
+			return "", fmt.Errorf("ui.listenTarget: %w", err)
 		}
+		p, err := board.ParsePoint(coords)
+		if err != nil {
+			log.Warn("app [handleShot]", "err", fmt.Errorf("board.ParsePoint: %w", err))
+			a.ui.setErrorText("Invalid coordinate, choose again!")
+			continue
+		}
+		coords = p.String()
 
-		if a.opponentBoard[x][y] == gui.Empty || a.opponentBoard[x][y] == gui.Ship {
+		if a.opponentBoard.At(p) == board.Unknown {
 			a.ui.resetErrorText()
-			log.Debug("app [handleShot]", "correct_coord", coords, "value", a.opponentBoard[x][y])
+			log.Debug("app [handleShot]", "correct_coord", coords)
 			cancel()
-			if a.opponentBoard[rec.x][rec.y] == gui.Ship {
-				a.opponentBoard[rec.x][rec.y] = gui.Empty
+			if a.pick != nil {
+				a.pick = nil
+				a.ui.setHeatmap(nil)
+				a.ui.setExplanation(nil)
 				a.updateBoard()
 			}
 			return coords, nil
 		}
 
-		log.Warn("app [handleShot]", "wrong_coord", coords, "value", a.opponentBoard[x][y])
+		log.Warn("app [handleShot]", "wrong_coord", coords, "value", a.opponentBoard.At(p))
 		a.ui.setErrorText("Choose again!")
 	}
 }
@@ -216,40 +374,45 @@ func (a *App) shoot(ctx context.Context) error {
 			return fmt.Errorf("handleShot: %w", err)
 		}
 
-		makeRequest(func() error {
-			answer, err = a.client.Fire(coord)
+		err = makeRequest(ctx, func() error {
+			answer, err = a.client.Fire(ctx, coord)
+			if retry.MaybeDelivered(err) {
+				// the shot may have been taken, firing again would be
+				// rejected as a repeated shot
+				return retry.Permanent(err)
+			}
 			return err
 		})
-		if err != nil {
+		if retry.MaybeDelivered(err) {
+			log.Warn("app [shoot] - no answer for the shot", "coord", coord, "err", err)
+			answer, err = a.resolveShot(ctx, coord)
+			if err != nil {
+				return fmt.Errorf("app.resolveShot: %w", err)
+			}
+			if answer.Result == "" {
+				// the game is over, the status says how it ended
+				return nil
+			}
+		} else if err != nil {
 			if errors.Is(err, client.ErrBadRequest) {
+				log.Warn("app [shoot] - shot rejected", "reason", serverReason(err))
 				return ErrorGameEnded
 			}
 			return fmt.Errorf("client.Fire: %w", err)
 		}
 
-		x, y, err := parseCoords(coord)
+		p, err := board.ParsePoint(coord)
 		if err != nil {
-			return fmt.Errorf("parseCoords: %w", err)
-
+			return fmt.Errorf("board.ParsePoint: %w", err)
 		}
 
-		a.totalShots++
-		switch answer.Result {
-		case "hit":
-			a.opponentBoard[x][y] = gui.Hit
-			a.bot.hit(a.opponentBoard, x, y)
-			a.hits++
-		case "miss":
-			a.opponentBoard[x][y] = gui.Miss
-		case "sunk":
-			a.opponentBoard[x][y] = gui.Hit
-			a.handleSunk(x, y)
-			a.hits++
-		}
+		a.recordShot(p, answer.Result)
+		a.record(replay.Event{Type: replay.TypeShot, Coord: coord, Result: answer.Result, Timer: a.status.Timer})
+		a.saveSession()
 
-		a.updateBoard()
 		a.ui.updateAccuracy(a.getAccuracy())
-		err = a.updateStatus()
+		a.updateBoard()
+		err = a.updateStatus(ctx)
 		if err != nil {
 			return fmt.Errorf("app.updateStatus: %w", err)
 		}
@@ -257,64 +420,128 @@ func (a *App) shoot(ctx context.Context) error {
 	return nil
 }
 
-func (a *App) initGame(payload models.GamePayload) error {
+// resolveShot finds out what became of a shot the server did not answer.
+// A miss passes the turn to the opponent. Otherwise the shot is fired again,
+// the server rejects it when the first one hit. The sunk ships cannot be told
+// apart from the hits then, they are recorded as hits. The result is empty
+// when the game ended meanwhile.
+func (a *App) resolveShot(ctx context.Context, coord string) (models.FireAnswer, error) {
+	for attempt := 1; ; attempt++ {
+		err := a.updateStatus(ctx)
+		if err != nil {
+			return models.FireAnswer{}, fmt.Errorf("app.updateStatus: %w", err)
+		}
+		if !a.gameInProgress() {
+			return models.FireAnswer{}, nil
+		}
+		if !a.status.ShouldFire {
+			return models.FireAnswer{Result: "miss"}, nil
+		}
+
+		answer, err := a.client.Fire(ctx, coord)
+		switch {
+		case err == nil:
+			return answer, nil
+		case errors.Is(err, client.ErrBadRequest):
+			log.Warn("app [resolveShot] - the shot was taken", "coord", coord, "reason", serverReason(err))
+			return models.FireAnswer{Result: "hit"}, nil
+		case !retry.MaybeDelivered(err) && !retry.IsTransient(err), attempt == retryPolicy.MaxAttempts:
+			return models.FireAnswer{}, fmt.Errorf("client.Fire: %w", err)
+		}
+		log.Warn("app [resolveShot] - no answer again", "coord", coord, "attempt", attempt, "err", err)
+		err = sleep(ctx, retryPolicy.BaseDelay)
+		if err != nil {
+			return models.FireAnswer{}, err
+		}
+	}
+}
+
+func (a *App) initGame(ctx context.Context, payload models.GamePayload) error {
+	if len(payload.Coords) > 0 {
+		_, err := board.Validate(payload.Coords)
+		if err != nil {
+			return fmt.Errorf("board.Validate: %w", err)
+		}
+	}
+
 	a.reset()
-	if a.useBot = promptPlayer("Do you want a bot to play for you?"); !a.useBot {
-		a.useAssistant = promptPlayer("Do you want to play with an assistant?")
+	if len(payload.Coords) > 0 {
+		a.gameBoard = a.boardName
+	}
+	if a.unattended {
+		a.useBot = true
+	} else {
+		var err error
+		a.useBot, err = promptPlayer("Do you want a bot to play for you?")
+		if err != nil {
+			return fmt.Errorf("promptPlayer: %w", err)
+		}
+		if !a.useBot {
+			a.useAssistant, err = promptPlayer("Do you want to play with an assistant?")
+			if err != nil {
+				return fmt.Errorf("promptPlayer: %w", err)
+			}
+		}
 	}
 
-	var err error
-	makeRequest(func() error {
-		err = a.client.InitGame(payload)
+	name, err := a.chooseStrategy(!a.unattended && (a.useBot || a.useAssistant))
+	if err != nil {
+		return fmt.Errorf("app.chooseStrategy: %w", err)
+	}
+	err = a.initStrategy(name)
+	if err != nil {
+		return fmt.Errorf("app.initStrategy: %w", err)
+	}
+
+	err = makeRequest(ctx, func() error {
+		err = a.client.InitGame(ctx, payload)
 		return err
 	})
 	if err != nil {
 		return fmt.Errorf("client.InitGame: %w", err)
 	}
 
-	refreshCtx, cancelRefresh := context.WithCancel(context.Background())
+	refreshCtx, cancelRefresh := context.WithCancel(ctx)
 	defer cancelRefresh()
 	go func() {
-		for {
-			time.Sleep(10 * time.Second)
-			select {
-			case <-refreshCtx.Done():
-				return
-			default:
-				makeRequest(func() error {
-					err = a.client.RefreshSession()
-					return err
-				})
-				if err != nil {
-					log.Error("app [initGame]", "err", fmt.Errorf("client.RefreshSession: %w", err))
-				}
+		for sleep(refreshCtx, 10*time.Second) == nil {
+			var err error
+			err = makeRequest(refreshCtx, func() error {
+				err = a.client.RefreshSession(refreshCtx)
+				return err
+			})
+			if err != nil && refreshCtx.Err() == nil {
+				log.Error("app [initGame]", "err", fmt.Errorf("client.RefreshSession: %w", err))
 			}
 		}
 	}()
 
-	err = a.updateStatus()
+	err = a.updateStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("app.updateStatus: %w", err)
 	}
 
 	log.Info("app [initGame] - waiting for the game to start")
 	for !a.gameInProgress() {
-		time.Sleep(time.Second)
-		err = a.updateStatus()
+		err = sleep(ctx, time.Second)
+		if err != nil {
+			return err
+		}
+		err = a.updateStatus(ctx)
 		if err != nil {
 			return fmt.Errorf("app.updateStatus: %w", err)
 		}
 	}
 	cancelRefresh()
 
-	err = a.updateDescription()
+	err = a.updateDescription(ctx)
 	if err != nil {
 		return fmt.Errorf("app.updateDescription: %w", err)
 	}
 
-	var board models.Board
-	makeRequest(func() error {
-		board, err = a.client.GetBoard()
+	var b models.Board
+	err = makeRequest(ctx, func() error {
+		b, err = a.client.GetBoard(ctx)
 		return err
 	})
 	if err != nil {
@@ -322,219 +549,114 @@ func (a *App) initGame(payload models.GamePayload) error {
 	}
 
 	log.Info("app [initGame] - parsing board")
-	err = a.parseBoard(board)
+	err = a.parseBoard(b)
 	if err != nil {
 		return fmt.Errorf("parseBoard: %w", err)
 	}
 	log.Info("app [initGame] - initializing gui")
 
-	a.ui = newGameUi()
-	a.ui.renderNicks(a.status.Nick, a.status.Opponent)
-	a.ui.renderDescriptions(a.status.Desc, a.status.OppDesc)
-
-	if a.useAssistant {
-		a.ui.addAssistantInfo()
-	}
-	a.updateBoard()
+	a.setupUi()
+	a.startRecording()
+	a.saveSession()
 	return nil
 }
 
-type point struct {
-	x, y int
-}
-
-func (a *App) editBoard() error {
-	fleet := map[int]int{4: 1, 3: 2, 2: 3, 1: 4}
-
-	ui := newFleetUi()
-	board := [10][10]gui.State{}
-	for i := range board {
-		board[i] = [10]gui.State{}
-		for j := range board[i] {
-			board[i][j] = gui.Hit
+func (a *App) SetStrategy(name string) error {
+	for _, n := range strategy.Names() {
+		if n == name {
+			a.strategyName = name
+			return nil
 		}
 	}
-	ui.board1.SetStates(board)
-	ctx, cancelFunc := context.WithCancel(context.Background())
-	defer cancelFunc()
-
-	go func() {
-		for length := 4; length >= 1; length-- {
-			count := fleet[length]
-			for s := 0; s < count; s++ {
-				var ship []point
-				ui.setInfoText(fmt.Sprintf("Placing ship with length: %d (%d/%d)", length, s+1, count))
-
-				for i := range board {
-					for j := range board[i] {
-						if board[i][j] == gui.Empty {
-							board[i][j] = gui.Hit
-						}
-					}
-				}
-				ui.board1.SetStates(board)
-
-				for j := 0; j < length; j++ {
-					ui.resetErrorText()
-					for {
-						coords := ui.board1.Listen(context.TODO())
-						x, y, err := parseCoords(coords)
-						if err != nil {
-							log.Error(fmt.Errorf("parseCoords: %w", err))
-						}
-
-						if board[x][y] == gui.Hit {
-							ship = append(ship, point{x, y})
-							board[x][y] = gui.Ship
-							clearHits(&board)
-							setPossiblePositions(&board, ship)
-							log.Debug("app [editBoard]", "board", board)
-							ui.board1.SetStates(board)
-							break
-						}
-						ui.setErrorText("Invalid choice")
-					}
-				}
-
-				clearHits(&board)
-				board = setImpossiblePositions(board, ship)
-				ui.board1.SetStates(board)
-				ui.setExitText("Press Ctrl+C to save and exit")
-			}
-		}
-
-		for i := range board {
-			for j := range board[i] {
-				if board[i][j] != gui.Ship {
-					board[i][j] = gui.Empty
-				}
-			}
-		}
-		a.customBoard = getCoordsFromBoard(board)
-		log.Debug("app [editBoard]", "coords", a.customBoard)
-	}()
-
-	ui.gui.Start(ctx, nil)
-
-	return nil
+	return fmt.Errorf("unknown strategy %q", name)
 }
 
-func (a *App) handleSunk(x, y int) {
-	log.Debug("app [handleSunk]", "x", x, "y", y)
-	ship := getShip(a.opponentBoard, x, y)
-	a.opponentBoard = setImpossiblePositions(a.opponentBoard, ship)
-	a.oppFleet[len(ship)]--
-	a.ui.setFleetInfo(a.oppFleet)
-	a.bot.sunk()
-}
+func (a *App) chooseStrategy(prompt bool) (string, error) {
+	if a.strategyName != "" {
+		return a.strategyName, nil
+	}
 
-func (a *App) reset() {
-	a.oppFleet = map[int]int{4: 1, 3: 2, 2: 3, 1: 4}
-	a.hits = 0
-	a.totalShots = 0
-	a.useBot = false
-	a.useAssistant = false
-	a.bot = newBot()
+	names := strategy.Names()
+	if !prompt || len(names) < 2 {
+		return strategy.Default, nil
+	}
+	fmt.Println("Choose a strategy:")
+	choice, err := promptList(names, 1, func(n string) string { return n })
+	if err != nil {
+		return "", fmt.Errorf("promptList: %w", err)
+	}
+	return names[choice-1], nil
 }
 
-func clearHits(board *[10][10]gui.State) {
-	for i := range board {
-		for j := range board[i] {
-			if board[i][j] == gui.Hit {
-				board[i][j] = gui.Empty
-			}
-		}
+func (a *App) initStrategy(name string) error {
+	var err error
+	a.strategy, err = strategy.New(name, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		return fmt.Errorf("strategy.New: %w", err)
 	}
+	a.currentStrategy = name
+	log.Info("app [initStrategy]", "strategy", name)
+	return nil
 }
 
-func setPossiblePositions(board *[10][10]gui.State, ship []point) {
-	neighbours := []point{
-		{0, 1},
-		{1, 0},
-		{0, -1},
-		{-1, 0},
+func (a *App) strategyState() strategy.State {
+	state := strategy.State{
+		Board: a.opponentBoard,
+		Fleet: a.oppFleet.Clone(),
 	}
-
-	for _, p := range ship {
-		log.Debug("app [setPossiblePositions]", "ship", p)
-		for _, offset := range neighbours {
-			n := point{p.x + offset.x, p.y + offset.y}
-			if n.x < 0 || n.x >= 10 || n.y < 0 || n.y >= 10 {
-				continue
-			}
-
-			log.Debug("app [setPossiblePositions]", "neighbour", n)
-
-			if board[n.x][n.y] == gui.Empty {
-				board[n.x][n.y] = gui.Hit
-			}
+	for _, shot := range a.shots {
+		p, err := board.ParsePoint(shot.Coord)
+		if err != nil {
+			log.Error("app [strategyState]", "err", fmt.Errorf("board.ParsePoint: %w", err))
+			continue
 		}
+		state.Shots = append(state.Shots, strategy.Shot{Point: p, Result: shot.Result})
 	}
-
-	log.Debug("app [setPossiblePositions]", "board", board)
+	return state
 }
 
-func setImpossiblePositions(board Board, ship []point) Board {
-	neighbours := []point{
-		{0, 1},
-		{1, 1},
-		{1, 0},
-		{1, -1},
-		{0, -1},
-		{-1, -1},
-		{-1, 0},
-		{-1, 1},
-	}
-
-	for _, p := range ship {
-		log.Debug("app [setImpossiblePositions]", "ship", p)
-		for _, offset := range neighbours {
-			n := point{p.x + offset.x, p.y + offset.y}
-			if n.x < 0 || n.x >= 10 || n.y < 0 || n.y >= 10 {
-				continue
-			}
-
-			log.Debug("app [setImpossiblePositions]", "neighbour", n)
-
-			if board[n.x][n.y] == gui.Empty {
-				board[n.x][n.y] = gui.Miss
-			}
-		}
+func (a *App) setupUi() {
+	if a.textMode {
+		a.ui = newTextUi(os.Stdout)
+	} else {
+		a.ui = newGameUi()
 	}
+	a.inferred = false
+	a.ui.renderNicks(a.status.Nick, a.status.Opponent)
+	a.ui.renderDescriptions(a.status.Desc, a.status.OppDesc)
 
-	log.Debug("app [setImpossiblePositions]", "board", board)
-	return board
+	if a.useAssistant {
+		a.ui.addAssistantInfo()
+	}
+	a.updateBoard()
 }
 
-func getShip(board [10][10]gui.State, x, y int) []point {
-	var ship []point
-	visited := make(map[point]struct{})
-	toVisit := []point{{x, y}}
-
-	neighbours := []point{
-		{0, 1},
-		{1, 0},
-		{0, -1},
-		{-1, 0},
-	}
-
-	for len(toVisit) > 0 {
-		var curr point
-		curr, toVisit = toVisit[0], toVisit[1:]
-		if board[curr.x][curr.y] == gui.Hit {
-			for _, offset := range neighbours {
-				n := point{curr.x + offset.x, curr.y + offset.y}
-				if _, ok := visited[n]; ok || n.x < 0 || n.x >= 10 || n.y < 0 || n.y >= 10 {
-					continue
-				}
-				toVisit = append(toVisit, n)
-			}
-			ship = append(ship, curr)
-		}
-
-		visited[curr] = struct{}{}
+func (a *App) recordShot(p board.Point, result string) {
+	a.totalShots++
+	if result != "miss" {
+		a.hits++
 	}
+	if ship := a.opponentBoard.Record(p, result); ship != nil {
+		a.handleSunk(ship)
+	}
+	a.shots = append(a.shots, savedShot{Coord: p.String(), Result: result})
+}
+
+func (a *App) handleSunk(ship board.Ship) {
+	log.Debug("app [handleSunk]", "ship", ship)
+	a.oppFleet[len(ship)]--
+	a.ui.setFleetInfo(a.oppFleet)
+	a.ui.setSunk(ship)
+}
 
-	log.Debug("app [getShip]", "ship", ship)
-	return ship
+func (a *App) reset() {
+	a.oppFleet = board.StandardFleet()
+	a.pick = nil
+	a.gameBoard = ""
+	a.hits = 0
+	a.totalShots = 0
+	a.useBot = false
+	a.useAssistant = false
+	a.shots = nil
+	a.oppShotsSeen = 0
 }
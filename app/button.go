@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+)
+
+var (
+	buttonBg = tl.RgbTo256Color(70, 70, 70)
+	buttonFg = tl.RgbTo256Color(208, 208, 208)
+)
+
+type button struct {
+	id     uuid.UUID
+	rec    *tl.Rectangle
+	txt    *tl.Text
+	key    rune
+	action string
+	ch     chan<- string
+}
+
+// newButton creates a clickable label sending action to ch when it is
+// clicked or when key is pressed.
+func newButton(x, y int, action string, key rune, ch chan<- string) *button {
+	label := fmt.Sprintf("%s (%c)", action, key)
+	return &button{
+		id:     uuid.New(),
+		rec:    tl.NewRectangle(x, y, len(label)+2, 1, buttonBg),
+		txt:    tl.NewText(x+1, y, label, buttonFg, buttonBg),
+		key:    key,
+		action: action,
+		ch:     ch,
+	}
+}
+
+func (b *button) ID() uuid.UUID {
+	return b.id
+}
+
+func (b *button) Drawables() []tl.Drawable {
+	return []tl.Drawable{b, b.txt}
+}
+
+func (b *button) Draw(s *tl.Screen) {
+	b.rec.Draw(s)
+}
+
+func (b *button) Tick(e tl.Event) {
+	x, y := b.rec.Position()
+	w, h := b.rec.Size()
+	clicked := e.Type == tl.EventMouse && e.Key == tl.MouseLeft &&
+		e.MouseX >= x && e.MouseX < x+w && e.MouseY >= y && e.MouseY < y+h
+	if !clicked && (e.Type != tl.EventKey || e.Ch != b.key) {
+		return
+	}
+
+	select {
+	case b.ch <- b.action:
+	default:
+		// drop, the previous action is still being handled
+	}
+}
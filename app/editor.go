@@ -0,0 +1,225 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+	"time"
+)
+
+const (
+	actionDraw   = "Draw"
+	actionSelect = "Select"
+	actionRotate = "Rotate"
+	actionMirror = "Mirror"
+	actionDelete = "Delete"
+	actionUndo   = "Undo"
+	actionRedo   = "Redo"
+	actionRandom = "Random"
+	actionClear  = "Clear"
+	actionSave   = "Save"
+)
+
+var editorButtons = []struct {
+	action string
+	key    rune
+}{
+	{actionDraw, 'd'},
+	{actionSelect, 's'},
+	{actionRotate, 'r'},
+	{actionMirror, 'm'},
+	{actionDelete, 'x'},
+	{actionUndo, 'u'},
+	{actionRedo, 'y'},
+	{actionRandom, 'g'},
+	{actionClear, 'c'},
+	{actionSave, 'w'},
+}
+
+type fleetEditor struct {
+	editor   *board.Editor
+	ui       *ui
+	validity *gui.Text
+	selectOn bool
+	selected board.Ship
+	grab     board.Point
+	rand     *rand.Rand
+}
+
+func (a *App) editBoard(ctx context.Context, start []string) error {
+	e, err := board.NewEditor(start)
+	if err != nil {
+		return fmt.Errorf("board.NewEditor: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	actions := make(chan string, 1)
+	fe := &fleetEditor{
+		editor:   e,
+		ui:       newFleetUi(),
+		validity: gui.NewText(2, 30, "", textConfig),
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	fe.ui.gui.Draw(fe.validity)
+	for i, b := range editorButtons {
+		fe.ui.gui.Draw(newButton(50, 6+2*i, b.action, b.key, actions))
+	}
+	fe.setMode(false)
+	fe.render()
+
+	clicks := make(chan string)
+	go func() {
+		for {
+			coord := fe.ui.board1.Listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case clicks <- coord:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case coord := <-clicks:
+				p, err := board.ParsePoint(coord)
+				if err != nil {
+					log.Error("app [editBoard]", "err", fmt.Errorf("board.ParsePoint: %w", err))
+					continue
+				}
+				fe.click(p)
+			case action := <-actions:
+				if action == actionSave && fe.editor.Validate() == nil {
+					a.setCustomBoard(fe.editor.Coords(), "")
+					log.Debug("app [editBoard]", "coords", a.customBoard)
+					cancel()
+					return
+				}
+				fe.do(action)
+			}
+			fe.render()
+		}
+	}()
+
+	fe.ui.gui.Start(ctx, nil)
+	return nil
+}
+
+func (fe *fleetEditor) click(p board.Point) {
+	fe.ui.resetErrorText()
+	if !fe.selectOn {
+		fe.editor.Toggle(p)
+		return
+	}
+
+	if ship := fe.editor.ShipAt(p); ship != nil {
+		fe.selected, fe.grab = ship, p
+		return
+	}
+	if fe.selected == nil {
+		return
+	}
+
+	moved, err := fe.editor.Move(fe.selected, fe.grab, p)
+	if err != nil {
+		fe.ui.setErrorText(err.Error())
+		return
+	}
+	fe.selected, fe.grab = moved, p
+}
+
+func (fe *fleetEditor) do(action string) {
+	fe.ui.resetErrorText()
+	var err error
+	switch action {
+	case actionDraw:
+		fe.setMode(false)
+	case actionSelect:
+		fe.setMode(true)
+	case actionRotate, actionMirror, actionDelete:
+		if fe.selected == nil {
+			fe.ui.setErrorText("Select a ship first")
+			return
+		}
+		switch action {
+		case actionRotate:
+			fe.selected, err = fe.editor.Rotate(fe.selected)
+			fe.grab = fe.selected[0]
+		case actionMirror:
+			fe.selected, err = fe.editor.Mirror(fe.selected)
+			fe.grab = fe.selected[0]
+		case actionDelete:
+			fe.editor.Remove(fe.selected)
+			fe.selected = nil
+		}
+	case actionUndo:
+		fe.selected = nil
+		if !fe.editor.Undo() {
+			err = fmt.Errorf("nothing to undo")
+		}
+	case actionRedo:
+		fe.selected = nil
+		if !fe.editor.Redo() {
+			err = fmt.Errorf("nothing to redo")
+		}
+	case actionRandom:
+		fe.selected = nil
+		err = fe.editor.Replace(board.RandomLayout(fe.rand).Coords())
+	case actionClear:
+		fe.selected = nil
+		err = fe.editor.Replace(nil)
+	case actionSave:
+		err = fmt.Errorf("the board is invalid, fix it before saving")
+	}
+	if err != nil {
+		fe.ui.setErrorText(err.Error())
+	}
+}
+
+func (fe *fleetEditor) setMode(selectOn bool) {
+	fe.selectOn = selectOn
+	fe.selected = nil
+	if selectOn {
+		fe.ui.setInfoText("Click a ship to select it, then click an empty cell to move it there")
+	} else {
+		fe.ui.setInfoText("Click cells to add or remove them")
+	}
+}
+
+func (fe *fleetEditor) render() {
+	states := boardStates(fe.editor.Board())
+	for _, p := range fe.selected {
+		states[p.X][p.Y] = gui.Hit
+	}
+	fe.ui.board1.SetStates(states)
+
+	err := fe.editor.Validate()
+	if err == nil {
+		fe.validity.SetFgColor(gui.Green)
+		fe.validity.SetText("The board is valid, press Save to use it")
+		return
+	}
+
+	text := err.Error()
+	var invalid *board.ValidationError
+	if errors.As(err, &invalid) {
+		text = invalid.Violations[0].Message
+		if len(invalid.Violations) > 1 {
+			text += fmt.Sprintf(" (and %d more)", len(invalid.Violations)-1)
+		}
+	}
+	fe.validity.SetFgColor(gui.Red)
+	fe.validity.SetText(text)
+}
@@ -1,27 +1,67 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/charmbracelet/log"
 	gui "github.com/grupawp/warships-gui/v2"
 	"github.com/mitchellh/go-wordwrap"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/config"
+	"github.com/wojtekolesinski/battleships/strategy"
 	"strings"
 )
 
+// gameUi is the front end driven by the game loop.
+type gameUi interface {
+	start(ctx context.Context)
+	renderNicks(playerNick, oppNick string)
+	renderDescriptions(playerDesc, oppDesc string)
+	renderBoards(player, opponent board.Board, pick *board.Point)
+	listenTarget(ctx context.Context) (string, error)
+	setFleetInfo(fleet board.Fleet)
+	setInfoText(text string)
+	setExitText(text string)
+	setErrorText(text string)
+	resetErrorText()
+	renderGameResult(result string)
+	updateTime(time int)
+	setThinking(thinking bool)
+	updateAccuracy(accuracy float32)
+	addAssistantInfo()
+	setHeatmap(heat *heatmap)
+	setExplanation(lines []string)
+	setInference(inf strategy.Inference)
+	setSunk(ship board.Ship)
+}
+
+var _ gameUi = (*ui)(nil)
+
 type ui struct {
-	gui       *gui.GUI
-	board1    *gui.Board
-	board2    *gui.Board
-	infoText  *gui.Text
-	errorText *gui.Text
-	exitText  *gui.Text
-	timer     *gui.Text
-	statsInfo *gui.Text
-	fleetInfo []*gui.Text
+	gui        *gui.GUI
+	board1     *gui.Board
+	board2     *gui.Board
+	infoText   *gui.Text
+	errorText  *gui.Text
+	exitText   *gui.Text
+	timer      *gui.Text
+	thinking   *gui.Text
+	statsInfo  *gui.Text
+	fleetInfo  []*gui.Text
+	reasons    []*gui.Text
+	sunkInfo   *gui.Text
+	heatmap    *cellOverlay
+	marks      *cellOverlay
+	placements map[int]int
+	fleet      board.Fleet
+	isThinking bool
 }
 
+// the most lines of strategy.Recommendation.Lines
+const explanationLines = 8
+
 var (
-	modelFleet  = map[int]int{4: 1, 3: 2, 2: 3, 1: 4}
+	modelFleet  = board.StandardFleet()
 	boardConfig = &gui.BoardConfig{
 		RulerColor: gui.White,
 		TextColor:  gui.Black,
@@ -56,6 +96,31 @@ var (
 	}
 )
 
+// SetTheme changes the colours of the board tiles, it must be called before
+// the interface is created.
+func SetTheme(t config.Theme) error {
+	colors := []struct {
+		dst []*gui.Color
+		src config.Color
+	}{
+		{[]*gui.Color{&boardConfig.EmptyColor, &oppBoardConfig.EmptyColor}, t.Empty},
+		{[]*gui.Color{&boardConfig.HitColor, &oppBoardConfig.HitColor}, t.Hit},
+		{[]*gui.Color{&boardConfig.MissColor, &oppBoardConfig.MissColor}, t.Miss},
+		{[]*gui.Color{&boardConfig.ShipColor}, t.Ship},
+		{[]*gui.Color{&oppBoardConfig.ShipColor}, t.Pick},
+	}
+	for _, c := range colors {
+		r, g, b, err := c.src.RGB()
+		if err != nil {
+			return fmt.Errorf("config.Color.RGB: %w", err)
+		}
+		for _, dst := range c.dst {
+			*dst = gui.NewColor(r, g, b)
+		}
+	}
+	return nil
+}
+
 func newGameUi() *ui {
 	g := gui.NewGUI(false)
 	board1 := gui.NewBoard(2, 6, boardConfig)
@@ -67,7 +132,11 @@ func newGameUi() *ui {
 		FgColor: gui.NewColor(10, 10, 10),
 		BgColor: gui.NewColor(255, 0, 255),
 	})
+	thinking := gui.NewText(48, 17, "", textConfig)
 	statsInfo := gui.NewText(50, 20, "0.00%", textConfig)
+	heatmap := newCellOverlay(60, 6, heatmapKey)
+	marks := newCellOverlay(60, 6, 0)
+	sunkInfo := gui.NewText(60, 45, "", textConfig)
 
 	g.Draw(gui.NewText(2, 40, "Legend:", textConfig))
 	g.Draw(gui.NewText(2, 42, "   ", &gui.TextConfig{BgColor: boardConfig.ShipColor}))
@@ -83,19 +152,21 @@ func newGameUi() *ui {
 	fleetInfo = append(fleetInfo, gui.NewText(60, 40, "Opponent's ships:", textConfig))
 	g.Draw(fleetInfo[0])
 	for i := 0; i < 4; i++ {
-		info := gui.NewText(60, 41+i,
-			fmt.Sprintf("%d masted: (%d/%d)", 4-i, modelFleet[4-i], modelFleet[4-i]),
-			textConfig)
+		info := gui.NewText(60, 41+i, fleetLine(4-i, modelFleet, nil), textConfig)
 		fleetInfo = append(fleetInfo, info)
 		g.Draw(info)
 	}
 
 	g.Draw(board1)
 	g.Draw(board2)
+	g.Draw(heatmap)
+	g.Draw(marks)
+	g.Draw(sunkInfo)
 	g.Draw(exitText)
 	g.Draw(infoText)
 	g.Draw(errorText)
 	g.Draw(timer)
+	g.Draw(thinking)
 	g.Draw(statsInfo)
 	g.Draw(gui.NewText(48, 19, "Accuracy:", nil))
 
@@ -106,9 +177,14 @@ func newGameUi() *ui {
 		infoText:  infoText,
 		exitText:  exitText,
 		timer:     timer,
+		thinking:  thinking,
 		statsInfo: statsInfo,
 		fleetInfo: fleetInfo,
 		errorText: errorText,
+		heatmap:   heatmap,
+		marks:     marks,
+		sunkInfo:  sunkInfo,
+		fleet:     modelFleet,
 	}
 }
 
@@ -133,6 +209,48 @@ func newFleetUi() *ui {
 	}
 }
 
+// cellState maps a cell of the board model to the state the gui draws.
+func cellState(c board.Cell) gui.State {
+	switch c {
+	case board.Water, board.Excluded:
+		return gui.Miss
+	case board.Hit, board.Sunk:
+		return gui.Hit
+	case board.Occupied:
+		return gui.Ship
+	}
+	return gui.Empty
+}
+
+func boardStates(b board.Board) [board.Size][board.Size]gui.State {
+	var states [board.Size][board.Size]gui.State
+	for x := range b {
+		for y := range b[x] {
+			states[x][y] = cellState(b[x][y])
+		}
+	}
+	return states
+}
+
+func (u *ui) start(ctx context.Context) {
+	u.gui.Start(ctx, nil)
+}
+
+func (u *ui) renderBoards(player, opponent board.Board, pick *board.Point) {
+	u.board1.SetStates(boardStates(player))
+	states := boardStates(opponent)
+	if pick != nil {
+		states[pick.X][pick.Y] = gui.Ship
+	}
+	u.board2.SetStates(states)
+	u.heatmap.setPick(pick)
+	u.marks.setPick(pick)
+}
+
+func (u *ui) listenTarget(ctx context.Context) (string, error) {
+	return u.board2.Listen(ctx), ctx.Err()
+}
+
 func (u *ui) renderNicks(playerNick, oppNick string) {
 	log.Debug("app [renderNicks]", "playerNick", playerNick, "oppNick", oppNick)
 	u.gui.Draw(gui.NewText(2, 28, playerNick, textConfig))
@@ -152,9 +270,10 @@ func (u *ui) renderDescriptions(playerDesc, oppDesc string) {
 	}
 }
 
-func (u *ui) setFleetInfo(fleet map[int]int) {
+func (u *ui) setFleetInfo(fleet board.Fleet) {
+	u.fleet = fleet.Clone()
 	for i := 0; i < 4; i++ {
-		u.fleetInfo[i+1].SetText(fmt.Sprintf("%d masted: (%d/%d)", 4-i, fleet[4-i], modelFleet[4-i]))
+		u.fleetInfo[i+1].SetText(fleetLine(4-i, u.fleet, u.placements))
 	}
 }
 
@@ -190,7 +309,9 @@ func (u *ui) renderGameResult(result string) {
 
 func (u *ui) updateTime(time int) {
 	u.timer.SetText(fmt.Sprintf(" %ds ", time))
-	if time <= 5 {
+	if u.isThinking {
+		u.timer.SetBgColor(gui.NewColor(255, 165, 0))
+	} else if time <= 5 {
 		u.timer.SetBgColor(gui.NewColor(250, 0, 0))
 	} else if time > 55 {
 		u.timer.SetBgColor(gui.NewColor(255, 0, 255))
@@ -199,6 +320,17 @@ func (u *ui) updateTime(time int) {
 	}
 }
 
+func (u *ui) setThinking(thinking bool) {
+	u.isThinking = thinking
+	if thinking {
+		u.thinking.SetText("Thinking...")
+		u.timer.SetBgColor(gui.NewColor(255, 165, 0))
+	} else {
+		u.thinking.SetText("")
+		u.timer.SetBgColor(gui.NewColor(255, 0, 255))
+	}
+}
+
 func (u *ui) updateAccuracy(accuracy float32) {
 	u.statsInfo.SetText(fmt.Sprintf("%.2f%%", accuracy))
 }
@@ -206,4 +338,56 @@ func (u *ui) updateAccuracy(accuracy float32) {
 func (u *ui) addAssistantInfo() {
 	u.gui.Draw(gui.NewText(2, 46, "   ", &gui.TextConfig{BgColor: oppBoardConfig.ShipColor}))
 	u.gui.Draw(gui.NewText(6, 46, "assistant's pick", textConfig))
+
+	u.gui.Draw(gui.NewText(26, 42, fmt.Sprintf("Hit chance, press %c to toggle:", heatmapKey), textConfig))
+	for i := 0; i < 5; i++ {
+		u.gui.Draw(gui.NewText(26+4*i, 43, "   ", &gui.TextConfig{BgColor: rampColor(float64(i) / 4)}))
+	}
+	u.gui.Draw(gui.NewText(26, 44, "low", textConfig))
+	u.gui.Draw(gui.NewText(38, 44, "highest", textConfig))
+
+	u.gui.Draw(gui.NewText(106, 8, "Why this cell:", textConfig))
+	for i := 0; i < explanationLines; i++ {
+		r := gui.NewText(106, 10+i, "", textConfig)
+		u.reasons = append(u.reasons, r)
+		u.gui.Draw(r)
+	}
+}
+
+func (u *ui) setHeatmap(heat *heatmap) {
+	u.heatmap.setTiles(heat.tiles())
+}
+
+func (u *ui) setExplanation(lines []string) {
+	for i, r := range u.reasons {
+		text := ""
+		if i < len(lines) {
+			text = lines[i]
+		}
+		r.SetText(text)
+	}
+}
+
+func (u *ui) setInference(inf strategy.Inference) {
+	if u.placements == nil {
+		u.gui.Draw(gui.NewText(26, 40, "   ", &gui.TextConfig{BgColor: certainColor}))
+		u.gui.Draw(gui.NewText(30, 40, "ship certain", textConfig))
+		u.gui.Draw(gui.NewText(26, 41, "   ", &gui.TextConfig{BgColor: impossibleColor}))
+		u.gui.Draw(gui.NewText(30, 41, "no ship possible", textConfig))
+	}
+	u.placements = inf.Placements
+
+	marks := map[board.Point]tile{}
+	for _, p := range inf.Certain {
+		marks[p] = tile{color: certainColor}
+	}
+	for _, p := range inf.Impossible {
+		marks[p] = tile{color: impossibleColor}
+	}
+	u.marks.setTiles(marks)
+	u.setFleetInfo(u.fleet)
+}
+
+func (u *ui) setSunk(ship board.Ship) {
+	u.sunkInfo.SetText(fmt.Sprintf("Last sunk: %d masted %s", len(ship), ship))
 }
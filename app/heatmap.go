@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	heatmapSamples = 1000
+	heatmapKey     = 'h'
+)
+
+// heatmap holds the chance of a hit for every unknown cell, the other cells
+// are negative.
+type heatmap struct {
+	chance [board.Size][board.Size]float64
+	max    float64
+}
+
+var heatmapRamp = []gui.Color{
+	gui.NewColor(40, 60, 120),
+	gui.NewColor(60, 150, 160),
+	gui.NewColor(230, 200, 40),
+	gui.NewColor(240, 120, 30),
+	gui.NewColor(230, 30, 22),
+}
+
+// newHeatmap estimates the hit chances in the given state, sampling until
+// its deadline at the latest. It returns nil when no fleet layout fits the
+// board.
+func newHeatmap(state strategy.State) *heatmap {
+	probs, n := strategy.HitProbabilities(state, heatmapSamples, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if n == 0 {
+		return nil
+	}
+
+	heat := &heatmap{chance: probs}
+	for x := range heat.chance {
+		for y, v := range heat.chance[x] {
+			if state.Board[x][y] != board.Unknown {
+				heat.chance[x][y] = -1
+			} else if v > heat.max {
+				heat.max = v
+			}
+		}
+	}
+	return heat
+}
+
+// scale returns the chance relative to the highest one, so that the best
+// cells are always the hottest.
+func (h *heatmap) scale(v float64) float64 {
+	if h.max == 0 {
+		return 0
+	}
+	return v / h.max
+}
+
+func rampColor(t float64) gui.Color {
+	if t <= 0 {
+		return heatmapRamp[0]
+	}
+	if t >= 1 {
+		return heatmapRamp[len(heatmapRamp)-1]
+	}
+	pos := t * float64(len(heatmapRamp)-1)
+	i := int(pos)
+	frac := pos - float64(i)
+	from, to := heatmapRamp[i], heatmapRamp[i+1]
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + frac*(float64(b)-float64(a))) }
+	return gui.NewColor(mix(from.Red, to.Red), mix(from.Green, to.Green), mix(from.Blue, to.Blue))
+}
+
+// tiles colours the unknown cells by their chance relative to the best one.
+func (h *heatmap) tiles() map[board.Point]tile {
+	if h == nil {
+		return nil
+	}
+	tiles := map[board.Point]tile{}
+	for x := range h.chance {
+		for y, v := range h.chance[x] {
+			if v >= 0 {
+				tiles[board.Point{X: x, Y: y}] = tile{color: rampColor(h.scale(v)), text: fmt.Sprintf("%3.0f", 100*v)}
+			}
+		}
+	}
+	return tiles
+}
+
+func (h *heatmap) String() string {
+	var sb strings.Builder
+	sb.WriteString("Hit chance in %:\n  ")
+	for x := 0; x < board.Size; x++ {
+		fmt.Fprintf(&sb, "%4c", 'A'+x)
+	}
+	for y := 0; y < board.Size; y++ {
+		fmt.Fprintf(&sb, "\n%2d", y+1)
+		for x := 0; x < board.Size; x++ {
+			if h.chance[x][y] < 0 {
+				sb.WriteString("   .")
+			} else {
+				fmt.Fprintf(&sb, "%4.0f", 100*h.chance[x][y])
+			}
+		}
+	}
+	return sb.String()
+}
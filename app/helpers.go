@@ -1,75 +1,118 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/log"
-	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/client"
 	"github.com/wojtekolesinski/battleships/models"
+	"github.com/wojtekolesinski/battleships/replay"
 	"strconv"
+	"time"
 )
 
-func promptList[T any](list []T, start int, mapper func(T) string) int {
+func promptList[T any](list []T, start int, mapper func(T) string) (int, error) {
 	for i, el := range list {
 		fmt.Printf("(%d)\t%s\n", start+i, mapper(el))
 	}
 
-	var res string
-	var choice int
 	for {
 		fmt.Print("Your choice: ")
-		_, err := fmt.Scanf("%s", &res)
+		line, err := readLine()
 		if err != nil {
-			fmt.Println("Try again")
-			continue
+			return 0, err
 		}
-		choice, err = strconv.Atoi(res)
+		choice, err := strconv.Atoi(line)
 		if err != nil {
 			fmt.Println("Try again")
 			continue
 		}
 
 		if choice >= start && choice < len(list)+start {
-			return choice
+			return choice, nil
 		}
 	}
 }
 
-func makeRequest(target func() error) {
-	for i := 0; i < maxRequests; i++ {
-		err := target()
-		if err == nil {
-			return
-		}
+func makeRequest(ctx context.Context, target func() error) error {
+	err := retryPolicy.Do(ctx, func(context.Context) error {
+		return target()
+	})
+	if err != nil {
 		log.Error("app [makeRequest]", "err", err)
 	}
+	return err
+}
+
+func serverReason(err error) string {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Reason()
+	}
+	return err.Error()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
 
-func promptPlayer(prompt string) bool {
-	var res string
+func promptPlayer(prompt string) (bool, error) {
 	for {
 		fmt.Print(fmt.Sprintf("%s (y/n): ", prompt))
-		_, err := fmt.Scanln(&res)
-		if err == nil {
-			if res == "y" {
-				return true
-			} else if res == "n" {
-				return false
-			}
-		} else {
-			log.Error("app [promptPlayWithBot]", "err", err, "res", res)
+		res, err := readLine()
+		if err != nil {
+			return false, err
 		}
+		if res == "y" {
+			return true, nil
+		} else if res == "n" {
+			return false, nil
+		}
+		log.Error("app [promptPlayWithBot]", "res", res)
 	}
 
 }
 
-func parseCoords(coords string) (int, int, error) {
-	x := int(coords[0] - 'A')
-	y, err := strconv.Atoi(coords[1:])
-	y -= 1
-	if err != nil {
-		return -1, -1, err
+func promptPath(prompt string) (string, error) {
+	for {
+		fmt.Print(prompt)
+		path, err := readLine()
+		if err != nil {
+			return "", err
+		}
+		if path != "" {
+			return path, nil
+		}
+		log.Error("app [promptPath] - empty path")
+	}
+}
+
+// promptSeed reads the seed of a random board, a blank line picks one.
+func promptSeed() (int64, error) {
+	for {
+		fmt.Print("Seed (leave blank for a random one): ")
+		line, err := readLine()
+		if err != nil {
+			return 0, err
+		}
+		if line == "" {
+			return time.Now().UnixNano(), nil
+		}
+		seed, err := strconv.ParseInt(line, 10, 64)
+		if err == nil {
+			return seed, nil
+		}
+		fmt.Println("Try again")
 	}
-	return x, y, nil
 }
 
 func (a *App) gameInProgress() bool {
@@ -84,13 +127,33 @@ func (a *App) getAccuracy() float32 {
 }
 
 func (a *App) updateOppShots() {
-	for _, coord := range a.status.OppShots {
-		x, y, _ := parseCoords(coord)
+	for i, coord := range a.status.OppShots {
+		p, err := board.ParsePoint(coord)
+		if err != nil {
+			log.Error("app [updateOppShots]", "err", fmt.Errorf("board.ParsePoint: %w", err))
+			continue
+		}
 
-		if a.playerBoard[x][y] == gui.Ship {
-			a.playerBoard[x][y] = gui.Hit
-		} else if a.playerBoard[x][y] == gui.Empty {
-			a.playerBoard[x][y] = gui.Miss
+		result := "miss"
+		if a.playerBoard.At(p) == board.Occupied || a.playerBoard.At(p) == board.Hit {
+			a.playerBoard.Set(p, board.Hit)
+			result = "sunk"
+			for _, q := range a.playerBoard.Component(p, board.Hit, board.Occupied) {
+				if a.playerBoard.At(q) == board.Occupied {
+					result = "hit"
+				}
+			}
+		} else if a.playerBoard.At(p) == board.Unknown {
+			a.playerBoard.Set(p, board.Water)
+		}
+		if i >= a.oppShotsSeen {
+			a.record(replay.Event{Type: replay.TypeOppShot, Coord: coord, Result: result})
+		}
+	}
+	if len(a.status.OppShots) > a.oppShotsSeen {
+		a.oppShotsSeen = len(a.status.OppShots)
+		if a.gameInProgress() {
+			a.saveSession()
 		}
 	}
 }
@@ -116,30 +179,16 @@ func (a *App) getGamePayload(targetNick string) models.GamePayload {
 	return payload
 }
 
-func getCoordsFromBoard(board [10][10]gui.State) []string {
-	var coords []string
-
-	for x := range board {
-		for y := range board[x] {
-			if board[x][y] == gui.Ship {
-				coords = append(coords, fmt.Sprintf("%c%d", x+'A', y+1))
-			}
-		}
-	}
-
-	return coords
-}
-
 func (a *App) updateBoard() {
 	log.Debug("app [updateBoard]")
-	a.ui.board1.SetStates(a.playerBoard)
-	a.ui.board2.SetStates(a.opponentBoard)
+	a.updateInference()
+	a.ui.renderBoards(a.playerBoard, a.opponentBoard, a.pick)
 }
 
-func (a *App) updateDescription() (err error) {
+func (a *App) updateDescription(ctx context.Context) (err error) {
 	var status models.StatusData
-	makeRequest(func() error {
-		status, err = a.client.GetDescription()
+	err = makeRequest(ctx, func() error {
+		status, err = a.client.GetDescription(ctx)
 		return err
 	})
 	if err != nil {
@@ -155,32 +204,23 @@ func (a *App) updateDescription() (err error) {
 }
 
 func (a *App) parseBoard(b models.Board) error {
-	a.playerBoard = [10][10]gui.State{}
-	a.opponentBoard = [10][10]gui.State{}
-	for i := range a.playerBoard {
-		a.playerBoard[i] = [10]gui.State{}
-		a.opponentBoard[i] = [10]gui.State{}
-
-		for j := range a.playerBoard[i] {
-			a.playerBoard[i][j] = gui.Empty
-			a.opponentBoard[i][j] = gui.Empty
-		}
-	}
+	a.playerBoard = board.Board{}
+	a.opponentBoard = board.Board{}
 
 	for _, coords := range b.Board {
-		x, y, err := parseCoords(coords)
+		p, err := board.ParsePoint(coords)
 		if err != nil {
-			return fmt.Errorf("parseCoords: %w", err)
+			return fmt.Errorf("board.ParsePoint: %w", err)
 		}
-		a.playerBoard[x][y] = gui.Ship
+		a.playerBoard.Set(p, board.Occupied)
 	}
 	return nil
 }
 
-func (a *App) updateStatus() (err error) {
+func (a *App) updateStatus(ctx context.Context) (err error) {
 	var status models.StatusData
-	makeRequest(func() error {
-		status, err = a.client.GetStatus()
+	err = makeRequest(ctx, func() error {
+		status, err = a.client.GetStatus(ctx)
 		return err
 	})
 	if err != nil {
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"github.com/charmbracelet/log"
+	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/strategy"
+)
+
+var (
+	certainColor    = gui.NewColor(200, 0, 200)
+	impossibleColor = gui.NewColor(50, 50, 50)
+)
+
+// updateInference works out where the opponent's ships can still be, only
+// when our shots changed the board since the last time. Scripted games skip
+// it, nobody looks at the hints there.
+func (a *App) updateInference() {
+	if a.unattended || (a.inferred && a.inferredBoard == a.opponentBoard) {
+		return
+	}
+	a.inferred, a.inferredBoard = true, a.opponentBoard
+
+	inf := strategy.Infer(a.strategyState())
+	log.Debug("app [updateInference]", "placements", inf.Placements, "certain", len(inf.Certain), "impossible", len(inf.Impossible))
+	a.ui.setInference(inf)
+}
+
+func fleetLine(length int, fleet board.Fleet, placements map[int]int) string {
+	line := fmt.Sprintf("%d masted: (%d/%d)", length, fleet[length], modelFleet[length])
+	if n, ok := placements[length]; ok && fleet[length] > 0 {
+		line += fmt.Sprintf(", %d placements", n)
+	}
+	return line
+}
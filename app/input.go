@@ -0,0 +1,56 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// stdin is the only reader of os.Stdin, so that a pending read of the text
+// front end never swallows an answer meant for the menu.
+var stdin = newLineReader(os.Stdin)
+
+type lineReader struct {
+	r     io.Reader
+	once  sync.Once
+	lines chan string
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: r, lines: make(chan string)}
+}
+
+func (l *lineReader) readLine(ctx context.Context) (string, error) {
+	l.once.Do(func() { go l.scan() })
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case line, ok := <-l.lines:
+		if !ok {
+			return "", io.EOF
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+func (l *lineReader) scan() {
+	s := bufio.NewScanner(l.r)
+	for s.Scan() {
+		l.lines <- s.Text()
+	}
+	close(l.lines)
+}
+
+// readLine returns the next line of the standard input, the error wraps
+// io.EOF once the input is closed.
+func readLine() (string, error) {
+	line, err := stdin.readLine(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("stdin.readLine: %w", err)
+	}
+	return line, nil
+}
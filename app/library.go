@@ -0,0 +1,288 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type libraryBoard struct {
+	Name   string   `json:"name"`
+	Coords []string `json:"coords"`
+	Games  int      `json:"games"`
+	Wins   int      `json:"wins"`
+	// games in which the whole fleet was sunk and the opponent shots they
+	// took, losses by timeout or abandon are left out
+	Sunk      int `json:"sunk"`
+	SinkShots int `json:"sink_shots"`
+}
+
+type library struct {
+	Default string         `json:"default,omitempty"`
+	Boards  []libraryBoard `json:"boards"`
+}
+
+func (b libraryBoard) summary() string {
+	if b.Games == 0 {
+		return "no games yet"
+	}
+	res := fmt.Sprintf("%d games, %.0f%% won", b.Games, 100*float64(b.Wins)/float64(b.Games))
+	if b.Sunk > 0 {
+		res += fmt.Sprintf(", sunk after %.1f shots on average", float64(b.SinkShots)/float64(b.Sunk))
+	}
+	return res
+}
+
+func (l *library) find(name string) *libraryBoard {
+	for i := range l.Boards {
+		if l.Boards[i].Name == name {
+			return &l.Boards[i]
+		}
+	}
+	return nil
+}
+
+func (a *App) loadLibrary() (*library, error) {
+	l := &library{}
+	if a.libraryPath == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(a.libraryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	err = json.Unmarshal(data, l)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return l, nil
+}
+
+func (a *App) saveLibrary(l *library) error {
+	if a.libraryPath == "" {
+		return fmt.Errorf("no place to store the library")
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(a.libraryPath), 0700)
+	if err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	err = os.WriteFile(a.libraryPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
+
+func (a *App) setCustomBoard(coords []string, name string) {
+	a.customBoard = coords
+	a.boardName = name
+}
+
+func (a *App) useDefaultBoard() {
+	if len(a.customBoard) > 0 {
+		return
+	}
+
+	l, err := a.loadLibrary()
+	if err != nil {
+		log.Error("app [useDefaultBoard]", "err", fmt.Errorf("app.loadLibrary: %w", err))
+		return
+	}
+	if b := l.find(l.Default); b != nil {
+		a.setCustomBoard(b.Coords, b.Name)
+		log.Info("app [useDefaultBoard]", "name", b.Name)
+	}
+}
+
+func (a *App) recordBoardResult() {
+	result := a.status.LastGameStatus
+	if a.gameBoard == "" || (result != "win" && result != "lose") {
+		return
+	}
+
+	l, err := a.loadLibrary()
+	if err != nil {
+		log.Error("app [recordBoardResult]", "err", fmt.Errorf("app.loadLibrary: %w", err))
+		return
+	}
+	b := l.find(a.gameBoard)
+	if b == nil {
+		log.Warn("app [recordBoardResult] - board is no longer in the library", "name", a.gameBoard)
+		return
+	}
+
+	b.Games++
+	if result == "win" {
+		b.Wins++
+	} else if len(a.playerBoard.Find(board.Occupied)) == 0 {
+		// every ship cell was hit, the misses are all on the board
+		b.Sunk++
+		b.SinkShots += len(a.playerBoard.Find(board.Hit, board.Water))
+	}
+
+	err = a.saveLibrary(l)
+	if err != nil {
+		log.Error("app [recordBoardResult]", "err", fmt.Errorf("app.saveLibrary: %w", err))
+		return
+	}
+	log.Info("app [recordBoardResult]", "name", b.Name, "result", result)
+}
+
+func (a *App) libraryMenu() error {
+	for {
+		l, err := a.loadLibrary()
+		if err != nil {
+			return fmt.Errorf("app.loadLibrary: %w", err)
+		}
+
+		fmt.Println()
+		if len(l.Boards) == 0 {
+			fmt.Println("The library is empty")
+		}
+		for i, b := range l.Boards {
+			marks := ""
+			if b.Name == l.Default {
+				marks += " (default)"
+			}
+			if b.Name == a.boardName {
+				marks += " (selected)"
+			}
+			fmt.Printf("%d. %s%s - %s\n", i+1, b.Name, marks, b.summary())
+		}
+		fmt.Println()
+
+		choices := []string{
+			"Add the current board",
+			"Select a board",
+			"Preview a board",
+			"Rename a board",
+			"Delete a board",
+			"Mark a board as default",
+			"Back",
+		}
+		choice, err := promptList(choices, 1, func(a string) string { return a })
+		if err != nil {
+			return fmt.Errorf("promptList: %w", err)
+		}
+		if choice == len(choices) {
+			return nil
+		}
+		if choice == 1 {
+			err = a.addToLibrary(l)
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("app.addToLibrary: %w", err)
+			}
+			if err != nil {
+				fmt.Printf("\nCould not add the board: %s\n\n", err)
+			}
+			continue
+		}
+		if len(l.Boards) == 0 {
+			continue
+		}
+
+		fmt.Println("Choose a board:")
+		i, err := promptList(l.Boards, 1, func(b libraryBoard) string { return b.Name })
+		if err != nil {
+			return fmt.Errorf("promptList: %w", err)
+		}
+		i--
+		b := &l.Boards[i]
+		switch choice {
+		case 2:
+			a.setCustomBoard(b.Coords, b.Name)
+			continue
+		case 3:
+			err = board.Encode(os.Stdout, b.Coords, board.FormatGrid)
+			if err != nil {
+				return fmt.Errorf("board.Encode: %w", err)
+			}
+			continue
+		case 4:
+			name, err := promptName(l)
+			if err != nil {
+				return fmt.Errorf("promptName: %w", err)
+			}
+			if l.Default == b.Name {
+				l.Default = name
+			}
+			if a.boardName == b.Name {
+				a.boardName = name
+			}
+			b.Name = name
+		case 5:
+			if l.Default == b.Name {
+				l.Default = ""
+			}
+			if a.boardName == b.Name {
+				a.boardName = ""
+			}
+			l.Boards = append(l.Boards[:i], l.Boards[i+1:]...)
+		case 6:
+			l.Default = b.Name
+		}
+
+		err = a.saveLibrary(l)
+		if err != nil {
+			return fmt.Errorf("app.saveLibrary: %w", err)
+		}
+	}
+}
+
+func (a *App) addToLibrary(l *library) error {
+	if len(a.customBoard) == 0 {
+		return fmt.Errorf("there is no custom board, create or load one first")
+	}
+	_, err := board.Validate(a.customBoard)
+	if err != nil {
+		return err
+	}
+
+	name, err := promptName(l)
+	if err != nil {
+		return fmt.Errorf("promptName: %w", err)
+	}
+	l.Boards = append(l.Boards, libraryBoard{Name: name, Coords: a.customBoard})
+	err = a.saveLibrary(l)
+	if err != nil {
+		return fmt.Errorf("app.saveLibrary: %w", err)
+	}
+	a.boardName = name
+	return nil
+}
+
+func promptName(l *library) (string, error) {
+	for {
+		fmt.Print("Board name: ")
+		name, err := readLine()
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			log.Error("app [promptName] - empty name")
+			continue
+		}
+		if l.find(name) != nil {
+			fmt.Printf("There already is a board named %s\n", name)
+			continue
+		}
+		return name, nil
+	}
+}
@@ -1,15 +1,18 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
 	"github.com/wojtekolesinski/battleships/client"
 	"github.com/wojtekolesinski/battleships/models"
+	"math/rand"
 	"strconv"
 )
 
-func (a *App) displayMenu() (models.GamePayload, error) {
+func (a *App) displayMenu(ctx context.Context) (models.GamePayload, error) {
 	for {
 		choices := []string{
 			"Join a game",
@@ -17,14 +20,27 @@ func (a *App) displayMenu() (models.GamePayload, error) {
 			"Display top 10 stats",
 			"Display your stats",
 			"Modify your board",
+			"Board library",
+			"Replays and analysis",
 		}
 
-		choice := promptList(choices, 1, func(a string) string { return a })
+		saved, err := a.loadSession()
+		if err != nil {
+			log.Error("app [displayMenu]", "err", fmt.Errorf("app.loadSession: %w", err))
+		}
+		if saved != nil {
+			choices = append(choices, fmt.Sprintf("Resume game against %s", saved.Opponent))
+		}
+
+		choice, err := promptList(choices, 1, func(a string) string { return a })
+		if err != nil {
+			return models.GamePayload{}, fmt.Errorf("promptList: %w", err)
+		}
 		log.Debug("app [displayMenu]", "choice", choice)
 
 		switch choice {
 		case 1:
-			targetNick, err := a.getOpponent()
+			targetNick, err := a.getOpponent(ctx)
 			if err != nil {
 				return models.GamePayload{}, fmt.Errorf("app.getOpponent: %w", err)
 			}
@@ -33,30 +49,184 @@ func (a *App) displayMenu() (models.GamePayload, error) {
 			fmt.Println("Waiting for an invitation...")
 			return a.getGamePayload(""), nil
 		case 3:
-			err := a.displayTop10Stats()
+			err := a.DisplayTop10Stats(ctx)
 			if err != nil {
-				return models.GamePayload{}, fmt.Errorf("app.displayTop10Stats: %w", err)
+				return models.GamePayload{}, fmt.Errorf("app.DisplayTop10Stats: %w", err)
 			}
 		case 4:
-			err := a.displayPlayerStats()
+			err := a.DisplayPlayerStats(ctx, a.status.Nick)
 			if err != nil {
-				return models.GamePayload{}, fmt.Errorf("app.displayPlayerStats: %w", err)
+				return models.GamePayload{}, fmt.Errorf("app.DisplayPlayerStats: %w", err)
 			}
 		case 5:
-			err := a.editBoard()
+			err := a.boardMenu(ctx)
 			if err != nil {
-				return models.GamePayload{}, fmt.Errorf("app.editBoard: %w", err)
+				return models.GamePayload{}, fmt.Errorf("app.boardMenu: %w", err)
 			}
+		case 6:
+			err := a.libraryMenu()
+			if err != nil {
+				return models.GamePayload{}, fmt.Errorf("app.libraryMenu: %w", err)
+			}
+		case 7:
+			err := a.replayMenu(ctx)
+			if err != nil {
+				return models.GamePayload{}, fmt.Errorf("app.replayMenu: %w", err)
+			}
+		case 8:
+			a.resume = saved
+			return models.GamePayload{}, nil
 		}
 	}
 
 }
 
-func (a *App) displayTop10Stats() error {
+func (a *App) boardMenu(ctx context.Context) error {
+	choices := []string{
+		"Place your ships manually",
+		"Generate a random board",
+		"Load board from file",
+		"Save board to file",
+		"Let the server assign a board",
+	}
+
+	choice, err := promptList(choices, 1, func(a string) string { return a })
+	if err != nil {
+		return fmt.Errorf("promptList: %w", err)
+	}
+	switch choice {
+	case 1:
+		if a.textMode {
+			fmt.Print("\nThe editor needs the full-screen interface, generate or load a board instead\n\n")
+			return nil
+		}
+		start, err := a.chooseEditorStart()
+		if err != nil {
+			return fmt.Errorf("app.chooseEditorStart: %w", err)
+		}
+		err = a.editBoard(ctx, start)
+		if err != nil {
+			return fmt.Errorf("app.editBoard: %w", err)
+		}
+	case 2:
+		err := a.generateBoard()
+		if err != nil {
+			return fmt.Errorf("app.generateBoard: %w", err)
+		}
+	case 3:
+		path, err := promptPath("Path to the board file: ")
+		if err != nil {
+			return fmt.Errorf("promptPath: %w", err)
+		}
+		err = a.LoadBoard(path)
+		if err != nil {
+			fmt.Printf("\nCould not load the board: %s\n\n", err)
+		}
+	case 4:
+		if len(a.customBoard) == 0 {
+			fmt.Print("\nThere is no custom board to save\n\n")
+			return nil
+		}
+		fmt.Println("Choose a format:")
+		choice, err := promptList(board.Formats, 1, func(f string) string { return formatNames[f] })
+		if err != nil {
+			return fmt.Errorf("promptList: %w", err)
+		}
+		path, err := promptPath("Save the board as: ")
+		if err != nil {
+			return fmt.Errorf("promptPath: %w", err)
+		}
+		err = board.SaveFile(path, a.customBoard, board.Formats[choice-1])
+		if err != nil {
+			fmt.Printf("\nCould not save the board: %s\n\n", err)
+		}
+	case 5:
+		a.setCustomBoard(nil, "")
+	}
+	return nil
+}
+
+var formatNames = map[string]string{
+	board.FormatGrid:   "ASCII grid, as printed by the app",
+	board.FormatJSON:   `JSON, {"coords": ["A1", ...]}`,
+	board.FormatCoords: "list of coordinates",
+}
+
+func (a *App) LoadBoard(path string) error {
+	coords, err := board.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("board.LoadFile: %w", err)
+	}
+	a.setCustomBoard(coords, "")
+	log.Info("app [LoadBoard]", "path", path)
+	return nil
+}
+
+func (a *App) chooseEditorStart() ([]string, error) {
+	choices := []string{"Start from an empty board", "Start from a random board"}
+	if len(a.customBoard) > 0 {
+		choices = append(choices, "Start from your current board")
+	}
+
+	choice, err := promptList(choices, 1, func(a string) string { return a })
+	if err != nil {
+		return nil, fmt.Errorf("promptList: %w", err)
+	}
+	switch choice {
+	case 2:
+		seed, err := promptSeed()
+		if err != nil {
+			return nil, fmt.Errorf("promptSeed: %w", err)
+		}
+		fmt.Printf("Starting from the board of seed %d\n", seed)
+		log.Info("app [chooseEditorStart]", "seed", seed)
+		return board.RandomLayout(rand.New(rand.NewSource(seed))).Coords(), nil
+	case 3:
+		return a.customBoard, nil
+	}
+	return nil, nil
+}
+
+func (a *App) generateBoard() error {
+	styles := board.Styles()
+	fmt.Println("Choose a placement style:")
+	choice, err := promptList(styles, 1, func(s string) string { return s })
+	if err != nil {
+		return fmt.Errorf("promptList: %w", err)
+	}
+	style := styles[choice-1]
+
+	// every board gets a seed of its own, so any of them can be made again
+	seed, err := promptSeed()
+	if err != nil {
+		return fmt.Errorf("promptSeed: %w", err)
+	}
+	seeds := rand.New(rand.NewSource(seed))
+	for {
+		layout, err := board.Generate(style, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			return fmt.Errorf("board.Generate: %w", err)
+		}
+		b := layout.Board()
+		fmt.Printf("\n%s\nStyle %s, seed %d\n\n", b.String(), style, seed)
+		use, err := promptPlayer("Use this board?")
+		if err != nil {
+			return fmt.Errorf("promptPlayer: %w", err)
+		}
+		if use {
+			a.setCustomBoard(layout.Coords(), "")
+			log.Debug("app [generateBoard]", "style", style, "seed", seed, "coords", a.customBoard)
+			return nil
+		}
+		seed = seeds.Int63()
+	}
+}
+
+func (a *App) DisplayTop10Stats(ctx context.Context) error {
 	var stats models.StatsList
 	var err error
-	makeRequest(func() error {
-		stats, err = a.client.GetStats()
+	err = makeRequest(ctx, func() error {
+		stats, err = a.client.GetStats(ctx)
 		return err
 	})
 	if err != nil {
@@ -78,16 +248,16 @@ func (a *App) displayTop10Stats() error {
 	return nil
 }
 
-func (a *App) displayPlayerStats() error {
+func (a *App) DisplayPlayerStats(ctx context.Context, nick string) error {
 	var stats models.StatsNick
 	var err error
-	makeRequest(func() error {
-		stats, err = a.client.GetPlayerStats(a.status.Nick)
+	err = makeRequest(ctx, func() error {
+		stats, err = a.client.GetPlayerStats(ctx, nick)
 		return err
 	})
 	if err != nil {
 		if errors.Is(err, client.ErrNotFound) {
-			fmt.Println("\nNo stats for player\n")
+			fmt.Print("\nNo stats for player\n\n")
 			return nil
 		}
 		return fmt.Errorf("client.GetPlayerStats: %w", err)
@@ -108,11 +278,11 @@ func (a *App) displayPlayerStats() error {
 	return nil
 }
 
-func (a *App) getOpponent() (targetNick string, err error) {
+func (a *App) getOpponent(ctx context.Context) (targetNick string, err error) {
 	var players []models.ListData
 	fmt.Println("Fetching list of active players")
-	makeRequest(func() error {
-		players, err = a.client.GetPlayersList()
+	err = makeRequest(ctx, func() error {
+		players, err = a.client.GetPlayersList(ctx)
 		return err
 	})
 	if err != nil {
@@ -122,34 +292,22 @@ func (a *App) getOpponent() (targetNick string, err error) {
 
 	players = append([]models.ListData{{Nick: "wp_bot"}}, players...)
 
-	choice := promptList(players, 0, func(a models.ListData) string { return a.Nick })
+	choice, err := promptList(players, 0, func(a models.ListData) string { return a.Nick })
+	if err != nil {
+		err = fmt.Errorf("promptList: %w", err)
+		return
+	}
 
 	return players[choice].Nick, nil
 }
 
-func (a *App) getNameAndDescription() {
-	var name, desc string
-	for {
-		fmt.Print("Insert your name (leave blank to get one assigned): ")
-		_, err := fmt.Scanln(&name)
-		if err == nil || err.Error() == "unexpected newline" {
-			break
-		} else {
-			log.Error("app [getNameAndDescripiton]", "err", err, "name", name)
-		}
-
-	}
-
-	for {
-		fmt.Print("Insert your description (leave blank to get one assigned): ")
-		_, err := fmt.Scanln(&desc)
-		if err == nil || err.Error() == "unexpected newline" {
-			break
-		} else {
-			log.Error("app [getNameAndDescripiton]", "err", err, "desc", desc)
-		}
-
+func (a *App) getNameAndDescription() (err error) {
+	fmt.Print("Insert your name (leave blank to get one assigned): ")
+	a.status.Nick, err = readLine()
+	if err != nil {
+		return
 	}
-	a.status.Nick = name
-	a.status.Desc = desc
+	fmt.Print("Insert your description (leave blank to get one assigned): ")
+	a.status.Desc, err = readLine()
+	return
 }
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"sync"
+)
+
+type tile struct {
+	color gui.Color
+	text  string
+}
+
+// cellOverlay paints single cells of a gui.Board drawn at x, y and lets the
+// clicks through to the board. With a toggle key it can be hidden.
+type cellOverlay struct {
+	id      uuid.UUID
+	x, y    int
+	toggle  rune
+	mu      sync.Mutex
+	tiles   map[board.Point]tile
+	pick    *board.Point
+	visible bool
+}
+
+func newCellOverlay(x, y int, toggle rune) *cellOverlay {
+	return &cellOverlay{id: uuid.New(), x: x, y: y, toggle: toggle, visible: true}
+}
+
+func (o *cellOverlay) setTiles(tiles map[board.Point]tile) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tiles = tiles
+}
+
+// setPick leaves the cell of the assistant's pick uncovered.
+func (o *cellOverlay) setPick(pick *board.Point) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pick = pick
+}
+
+func (o *cellOverlay) ID() uuid.UUID {
+	return o.id
+}
+
+func (o *cellOverlay) Drawables() []tl.Drawable {
+	return []tl.Drawable{o}
+}
+
+func (o *cellOverlay) Tick(e tl.Event) {
+	if o.toggle != 0 && e.Type == tl.EventKey && e.Ch == o.toggle {
+		o.mu.Lock()
+		o.visible = !o.visible
+		o.mu.Unlock()
+	}
+}
+
+func (o *cellOverlay) Draw(s *tl.Screen) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.visible {
+		return
+	}
+	for p, t := range o.tiles {
+		if o.pick != nil && *o.pick == p {
+			continue
+		}
+		drawTile(s, o.x, o.y, p, attr(t.color), t.text)
+	}
+}
+
+// drawTile covers the tile of p with the same layout as gui.Board uses.
+func drawTile(s *tl.Screen, x, y int, p board.Point, bg tl.Attr, text string) {
+	tx, ty := x+(p.X+1)*4, y+(p.Y+1)*2
+	tl.NewRectangle(tx, ty, 3, 1, bg).Draw(s)
+	if text != "" {
+		tl.NewText(tx, ty, text, tl.ColorBlack, bg).Draw(s)
+	}
+}
+
+func attr(c gui.Color) tl.Attr {
+	return tl.RgbTo256Color(int(c.Red), int(c.Green), int(c.Blue))
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/replay"
+	"path/filepath"
+	"time"
+)
+
+// SetReplayDir changes the directory games are recorded to, an empty dir
+// disables recording.
+func (a *App) SetReplayDir(dir string) {
+	a.replayDir = dir
+}
+
+func (a *App) startRecording() {
+	if a.replayDir == "" {
+		return
+	}
+
+	a.openRecording(filepath.Join(a.replayDir, replay.FileName(time.Now(), a.status.Opponent)))
+	a.record(replay.Event{
+		Type:     replay.TypeStart,
+		Nick:     a.status.Nick,
+		Desc:     a.status.Desc,
+		Opponent: a.status.Opponent,
+		OppDesc:  a.status.OppDesc,
+		Board:    a.playerBoard.Coords(board.Occupied),
+		Strategy: a.currentStrategy,
+		Bot:      a.useBot,
+	})
+}
+
+func (a *App) openRecording(path string) {
+	if path == "" {
+		return
+	}
+	a.stopRecording("abandoned")
+
+	r, err := replay.Open(path)
+	if err != nil {
+		log.Error("app [openRecording]", "err", fmt.Errorf("replay.Open: %w", err))
+		return
+	}
+	a.recorder = r
+	log.Info("app [openRecording]", "path", path)
+}
+
+func (a *App) record(e replay.Event) {
+	if a.recorder == nil {
+		return
+	}
+
+	err := a.recorder.Record(e)
+	if err != nil {
+		log.Error("app [record]", "err", fmt.Errorf("replay.Recorder.Record: %w", err))
+	}
+}
+
+func (a *App) stopRecording(status string) {
+	if a.recorder == nil {
+		return
+	}
+
+	a.record(replay.Event{Type: replay.TypeEnd, Status: status})
+	err := a.recorder.Close()
+	if err != nil {
+		log.Error("app [stopRecording]", "err", fmt.Errorf("replay.Recorder.Close: %w", err))
+	}
+	a.recorder = nil
+}
+
+func (a *App) recordingPath() string {
+	if a.recorder == nil {
+		return ""
+	}
+	return a.recorder.Path()
+}
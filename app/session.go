@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/models"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var errCannotResume = fmt.Errorf("game cannot be resumed")
+
+type savedShot struct {
+	Coord  string `json:"coord"`
+	Result string `json:"result"`
+}
+
+type savedSession struct {
+	Token        string      `json:"token"`
+	Nick         string      `json:"nick"`
+	Desc         string      `json:"desc"`
+	Opponent     string      `json:"opponent"`
+	OppDesc      string      `json:"opp_desc"`
+	Board        []string    `json:"board"`
+	Shots        []savedShot `json:"shots"`
+	UseBot       bool        `json:"use_bot"`
+	UseAssistant bool        `json:"use_assistant"`
+	Strategy     string      `json:"strategy"`
+	BoardName    string      `json:"board_name,omitempty"`
+	Replay       string      `json:"replay,omitempty"`
+	OppShots     int         `json:"opp_shots"`
+	SavedAt      time.Time   `json:"saved_at"`
+}
+
+func configPath(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		log.Warn("app [configPath] - persistence disabled", "name", name, "err", err)
+		return ""
+	}
+	return filepath.Join(dir, "battleships", name)
+}
+
+func (a *App) saveSession() {
+	if a.sessionPath == "" {
+		return
+	}
+
+	s := savedSession{
+		Token:        a.client.Token(),
+		Nick:         a.status.Nick,
+		Desc:         a.status.Desc,
+		Opponent:     a.status.Opponent,
+		OppDesc:      a.status.OppDesc,
+		Board:        a.playerBoard.Coords(board.Occupied, board.Hit),
+		Shots:        a.shots,
+		UseBot:       a.useBot,
+		UseAssistant: a.useAssistant,
+		Strategy:     a.currentStrategy,
+		BoardName:    a.gameBoard,
+		Replay:       a.recordingPath(),
+		OppShots:     a.oppShotsSeen,
+		SavedAt:      time.Now(),
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		log.Error("app [saveSession]", "err", fmt.Errorf("json.MarshalIndent: %w", err))
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(a.sessionPath), 0700)
+	if err != nil {
+		log.Error("app [saveSession]", "err", fmt.Errorf("os.MkdirAll: %w", err))
+		return
+	}
+
+	err = os.WriteFile(a.sessionPath, data, 0600)
+	if err != nil {
+		log.Error("app [saveSession]", "err", fmt.Errorf("os.WriteFile: %w", err))
+		return
+	}
+	log.Debug("app [saveSession]", "path", a.sessionPath, "shots", len(s.Shots))
+}
+
+func (a *App) loadSession() (*savedSession, error) {
+	if a.sessionPath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(a.sessionPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var s savedSession
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return &s, nil
+}
+
+func (a *App) clearSession() {
+	if a.sessionPath == "" {
+		return
+	}
+
+	err := os.Remove(a.sessionPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error("app [clearSession]", "err", fmt.Errorf("os.Remove: %w", err))
+	}
+}
+
+func (a *App) resumeGame(ctx context.Context, s *savedSession) error {
+	log.Info("app [resumeGame]", "nick", s.Nick, "opponent", s.Opponent, "shots", len(s.Shots))
+	a.reset()
+	a.useBot = s.UseBot
+	a.useAssistant = s.UseAssistant
+	a.gameBoard = s.BoardName
+	a.oppShotsSeen = s.OppShots
+	a.client.SetToken(s.Token)
+
+	name := s.Strategy
+	if name == "" {
+		name = strategy.Default
+	}
+	err := a.initStrategy(name)
+	if err != nil {
+		a.clearSession()
+		return fmt.Errorf("%w: %s", errCannotResume, err)
+	}
+
+	err = a.updateStatus(ctx)
+	if err != nil {
+		a.clearSession()
+		return fmt.Errorf("%w: %s", errCannotResume, serverReason(err))
+	}
+	if !a.gameInProgress() {
+		a.clearSession()
+		return fmt.Errorf("%w: the game is no longer in progress", errCannotResume)
+	}
+
+	err = a.updateDescription(ctx)
+	if err != nil {
+		return fmt.Errorf("app.updateDescription: %w", err)
+	}
+
+	var b models.Board
+	err = makeRequest(ctx, func() error {
+		b, err = a.client.GetBoard(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("client.GetBoard: %w", err)
+	}
+
+	err = a.parseBoard(b)
+	if err != nil {
+		return fmt.Errorf("parseBoard: %w", err)
+	}
+	a.openRecording(s.Replay)
+	a.updateOppShots()
+
+	a.setupUi()
+	for _, shot := range s.Shots {
+		p, err := board.ParsePoint(shot.Coord)
+		if err != nil {
+			return fmt.Errorf("board.ParsePoint: %w", err)
+		}
+		a.recordShot(p, shot.Result)
+	}
+	a.ui.updateAccuracy(a.getAccuracy())
+	a.updateBoard()
+	a.saveSession()
+	return nil
+}
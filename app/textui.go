@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+const textLegend = "# ship   x hit   * sunk   ~ miss   - no ship possible   ! ship certain   . unknown"
+
+// textUi prints the game as plain text and reads targets typed on the
+// standard input, for terminals where the full-screen interface does not work.
+type textUi struct {
+	mu        sync.Mutex
+	out       io.Writer
+	player    board.Board
+	opponent  board.Board
+	pick      *board.Point
+	drawn     bool
+	nick      string
+	oppNick   string
+	info      string
+	timer     int
+	thinking  bool
+	accuracy  float32
+	fleet     board.Fleet
+	assistant bool
+	heat      *heatmap
+	hideHeat  bool
+	reasons   []string
+	inference *strategy.Inference
+}
+
+var _ gameUi = (*textUi)(nil)
+
+func newTextUi(out io.Writer) *textUi {
+	return &textUi{out: out, fleet: board.StandardFleet()}
+}
+
+func (u *textUi) start(ctx context.Context) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	select {
+	case <-ctx.Done():
+	case <-interrupt:
+		u.println("Leaving the game")
+	}
+}
+
+func (u *textUi) renderNicks(playerNick, oppNick string) {
+	u.mu.Lock()
+	u.nick, u.oppNick = playerNick, oppNick
+	u.mu.Unlock()
+	u.println(fmt.Sprintf("\n%s vs %s", playerNick, oppNick))
+	u.println("Press Ctrl+C to leave the game")
+}
+
+func (u *textUi) renderDescriptions(playerDesc, oppDesc string) {
+	u.println(fmt.Sprintf("%s: %s", u.nick, playerDesc))
+	u.println(fmt.Sprintf("%s: %s", u.oppNick, oppDesc))
+}
+
+func (u *textUi) renderBoards(player, opponent board.Board, pick *board.Point) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.drawn && player == u.player && opponent == u.opponent && samePoint(pick, u.pick) {
+		return
+	}
+	u.player, u.opponent, u.pick, u.drawn = player, opponent, pick, true
+
+	left := strings.Split(player.String(), "\n")
+	right := strings.Split(opponent.String(), "\n")
+	if u.inference != nil {
+		for _, p := range u.inference.Certain {
+			markCell(right, p, '!')
+		}
+		for _, p := range u.inference.Impossible {
+			markCell(right, p, '-')
+		}
+	}
+	if pick != nil {
+		markCell(right, *pick, '?')
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n%-26s%s\n", u.nick, u.oppNick)
+	for i := range left {
+		fmt.Fprintf(&sb, "%-26s%s\n", left[i], right[i])
+	}
+	sb.WriteString(textLegend)
+	if u.assistant {
+		fmt.Fprintf(&sb, "   ? assistant's pick   %c + Enter toggles the hit chances", heatmapKey)
+	}
+	fmt.Fprintf(&sb, "\nAccuracy: %.2f%%   Opponent's ships:", u.accuracy)
+	for size := 4; size > 0; size-- {
+		fmt.Fprintf(&sb, " %d masted (%d/%d", size, u.fleet[size], modelFleet[size])
+		if u.inference != nil && u.fleet[size] > 0 {
+			fmt.Fprintf(&sb, ", %d placements", u.inference.Placements[size])
+		}
+		sb.WriteString(")")
+	}
+	if u.heat != nil && !u.hideHeat {
+		fmt.Fprintf(&sb, "\n\n%s", u.heat)
+	}
+	if len(u.reasons) > 0 && pick != nil {
+		fmt.Fprintf(&sb, "\n\nWhy %s:\n%s", pick, strings.Join(u.reasons, "\n"))
+	}
+	fmt.Fprintln(u.out, sb.String())
+}
+
+func (u *textUi) listenTarget(ctx context.Context) (string, error) {
+	for {
+		u.mu.Lock()
+		timer := u.timer
+		u.mu.Unlock()
+		u.print(fmt.Sprintf("Target (%ds left): ", timer))
+
+		line, err := stdin.readLine(ctx)
+		if errors.Is(err, io.EOF) {
+			u.println("\nInput closed, leaving the game")
+		}
+		if err != nil {
+			return "", err
+		}
+		if line != string(heatmapKey) {
+			return strings.ToUpper(line), nil
+		}
+		u.toggleHeatmap()
+	}
+}
+
+func (u *textUi) toggleHeatmap() {
+	u.mu.Lock()
+	heat := u.heat
+	u.hideHeat = !u.hideHeat
+	hidden := u.hideHeat
+	u.mu.Unlock()
+
+	switch {
+	case heat == nil:
+		u.println("There are no hit chances to show")
+	case hidden:
+		u.println("Hit chances hidden")
+	default:
+		u.println(heat.String())
+	}
+}
+
+func (u *textUi) setFleetInfo(fleet board.Fleet) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.fleet = fleet.Clone()
+}
+
+func (u *textUi) setInfoText(text string) {
+	u.mu.Lock()
+	changed := text != u.info
+	u.info = text
+	u.mu.Unlock()
+	if changed {
+		u.println(text)
+	}
+}
+
+func (u *textUi) setExitText(text string) {
+	u.println(text)
+}
+
+func (u *textUi) setErrorText(text string) {
+	u.println(text)
+}
+
+func (u *textUi) resetErrorText() {}
+
+func (u *textUi) renderGameResult(result string) {
+	if result == "win" {
+		u.println("\nYou win")
+	} else if result == "lose" {
+		u.println("\nYou lose")
+	}
+}
+
+func (u *textUi) updateTime(time int) {
+	u.mu.Lock()
+	u.timer = time
+	thinking := u.thinking
+	u.mu.Unlock()
+	if !thinking && (time == 10 || time == 5) {
+		u.println(fmt.Sprintf("\n%ds left", time))
+	}
+}
+
+func (u *textUi) setThinking(thinking bool) {
+	u.mu.Lock()
+	u.thinking = thinking
+	u.mu.Unlock()
+	if thinking {
+		u.println("Thinking...")
+	}
+}
+
+func (u *textUi) updateAccuracy(accuracy float32) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.accuracy = accuracy
+}
+
+func (u *textUi) addAssistantInfo() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.assistant = true
+}
+
+func (u *textUi) setHeatmap(heat *heatmap) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.heat = heat
+}
+
+func (u *textUi) setExplanation(lines []string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.reasons = lines
+}
+
+func (u *textUi) setInference(inf strategy.Inference) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.inference = &inf
+}
+
+func (u *textUi) setSunk(ship board.Ship) {
+	u.println(fmt.Sprintf("Sunk a %d masted ship %s", len(ship), ship))
+}
+
+func (u *textUi) print(text string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	fmt.Fprint(u.out, text)
+}
+
+func (u *textUi) println(text string) {
+	u.print(text + "\n")
+}
+
+func markCell(rows []string, p board.Point, symbol byte) {
+	row := []byte(rows[p.Y+1])
+	row[3+2*p.X] = symbol
+	rows[p.Y+1] = string(row)
+}
+
+func samePoint(a, b *board.Point) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,345 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+	gui "github.com/grupawp/warships-gui/v2"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/replay"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	actionPlay   = "Play/Pause"
+	actionBack   = "Back"
+	actionNext   = "Next"
+	actionFirst  = "First"
+	actionLast   = "Last"
+	actionSlower = "Slower"
+	actionFaster = "Faster"
+	actionView   = "Switch fleet"
+)
+
+var viewerButtons = []struct {
+	action string
+	key    rune
+}{
+	{actionPlay, 'p'},
+	{actionBack, 'b'},
+	{actionNext, 'n'},
+	{actionFirst, 'a'},
+	{actionLast, 'e'},
+	{actionSlower, '-'},
+	{actionFaster, '+'},
+	{actionView, 'v'},
+}
+
+var playbackDelays = []time.Duration{
+	2 * time.Second,
+	time.Second,
+	500 * time.Millisecond,
+	250 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+type replayViewer struct {
+	game     *replay.Game
+	turns    []int
+	final    replay.Frame
+	ui       *ui
+	status   *gui.Text
+	step     int
+	playing  bool
+	delay    int
+	ownFleet bool
+}
+
+// ViewReplay shows a recorded game in the game layout, move by move.
+func (a *App) ViewReplay(ctx context.Context, path string) error {
+	g, err := replay.Load(path)
+	if err != nil {
+		return fmt.Errorf("replay.Load: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	v := &replayViewer{
+		game:     g,
+		turns:    g.Turns(),
+		final:    g.Frame(len(g.Moves)),
+		ui:       newGameUi(),
+		status:   gui.NewText(60, 4, "", textConfig),
+		delay:    1,
+		ownFleet: true,
+	}
+	v.ui.renderNicks(g.Start.Nick, g.Start.Opponent)
+	v.ui.renderDescriptions(g.Start.Desc, g.Start.OppDesc)
+	v.ui.setExitText("Press Ctrl+C to exit, type a turn number and Enter to jump to it")
+	v.ui.gui.Draw(v.status)
+
+	actions := make(chan string, 1)
+	x := 2
+	for i, b := range viewerButtons {
+		if i%4 == 0 {
+			x = 2
+		}
+		v.ui.gui.Draw(newButton(x, 48+2*(i/4), b.action, b.key, actions))
+		x += len(b.action) + 7
+	}
+	jumps := make(chan int, 1)
+	v.ui.gui.Draw(newNumberInput(2, 52, "Jump to turn: ", jumps))
+	v.render()
+
+	go func() {
+		for {
+			var tick <-chan time.Time
+			if v.playing {
+				tick = time.After(playbackDelays[v.delay])
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				v.seek(v.step + 1)
+				if v.step == len(g.Moves) {
+					v.playing = false
+				}
+			case action := <-actions:
+				v.do(action)
+			case turn := <-jumps:
+				v.jump(turn)
+			}
+			v.render()
+		}
+	}()
+
+	v.ui.gui.Start(ctx, nil)
+	return nil
+}
+
+func (v *replayViewer) do(action string) {
+	switch action {
+	case actionPlay:
+		v.playing = !v.playing
+		if v.playing && v.step == len(v.game.Moves) {
+			v.step = 0
+		}
+	case actionBack:
+		v.seek(v.step - 1)
+	case actionNext:
+		v.seek(v.step + 1)
+	case actionFirst:
+		v.seek(0)
+	case actionLast:
+		v.seek(len(v.game.Moves))
+	case actionSlower:
+		if v.delay > 0 {
+			v.delay--
+		}
+	case actionFaster:
+		if v.delay < len(playbackDelays)-1 {
+			v.delay++
+		}
+	case actionView:
+		v.ownFleet = !v.ownFleet
+	}
+}
+
+func (v *replayViewer) seek(step int) {
+	if step < 0 {
+		step = 0
+	}
+	if step > len(v.game.Moves) {
+		step = len(v.game.Moves)
+	}
+	v.step = step
+}
+
+func (v *replayViewer) jump(turn int) {
+	if turn <= 0 || len(v.turns) == 0 {
+		v.seek(0)
+		return
+	}
+	if turn > len(v.turns) {
+		turn = len(v.turns)
+	}
+	v.seek(v.turns[turn-1])
+}
+
+func (v *replayViewer) turn() int {
+	if v.step == 0 {
+		return 0
+	}
+	return sort.SearchInts(v.turns, v.step) + 1
+}
+
+func (v *replayViewer) render() {
+	f := v.game.Frame(v.step)
+
+	player := boardStates(f.Player)
+	opponent := boardStates(f.Opponent)
+	if !v.ownFleet {
+		for _, p := range f.Player.Find(board.Occupied) {
+			player[p.X][p.Y] = gui.Empty
+		}
+		for _, p := range v.final.Opponent.Find(board.Hit, board.Sunk) {
+			if f.Opponent.At(p) == board.Unknown {
+				opponent[p.X][p.Y] = gui.Ship
+			}
+		}
+	}
+	v.ui.board1.SetStates(player)
+	v.ui.board2.SetStates(opponent)
+	v.ui.setFleetInfo(f.Fleet)
+
+	var accuracy float32
+	if f.Shots > 0 {
+		accuracy = 100 * float32(f.Hits) / float32(f.Shots)
+	}
+	v.ui.updateAccuracy(accuracy)
+
+	info := fmt.Sprintf("Turn %d/%d, shot %d/%d", v.turn(), len(v.turns), v.step, len(v.game.Moves))
+	v.ui.timer.SetText(" --- ")
+	if e := f.Last; e != nil {
+		shooter := v.game.Start.Nick
+		if e.Type == replay.TypeOppShot {
+			shooter = v.game.Start.Opponent
+		} else {
+			v.ui.timer.SetText(fmt.Sprintf(" %ds ", e.Timer))
+		}
+		info += fmt.Sprintf(": %s fired at %s - %s", shooter, e.Coord, e.Result)
+	}
+	if v.step == len(v.game.Moves) && v.game.End != nil {
+		info += fmt.Sprintf(", game over: %s", v.game.End.Status)
+	}
+	v.ui.setInfoText(info)
+
+	state := "paused"
+	if v.playing {
+		state = fmt.Sprintf("playing, a move every %s", playbackDelays[v.delay])
+	}
+	fleet := "your fleet"
+	if !v.ownFleet {
+		fleet = "opponent's fleet as revealed by the end of the game"
+	}
+	v.status.SetText(fmt.Sprintf("Showing %s, %s", fleet, state))
+}
+
+func (a *App) replayMenu(ctx context.Context) error {
+	if a.replayDir == "" {
+		fmt.Print("\nGames are not recorded\n\n")
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(a.replayDir, "*.jsonl"))
+	if err != nil {
+		return fmt.Errorf("filepath.Glob: %w", err)
+	}
+	if len(paths) == 0 {
+		fmt.Print("\nThere are no replays yet\n\n")
+		return nil
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	if len(paths) > 20 {
+		paths = paths[:20]
+	}
+
+	fmt.Println("Choose a game:")
+	choice, err := promptList(paths, 1, func(p string) string {
+		return strings.TrimSuffix(filepath.Base(p), ".jsonl")
+	})
+	if err != nil {
+		return fmt.Errorf("promptList: %w", err)
+	}
+	path := paths[choice-1]
+
+	choices := []string{
+		"Watch the game",
+		"Show the analysis of your shots",
+		"Export the analysis (.md, .html or .txt)",
+	}
+	choice, err = promptList(choices, 1, func(c string) string { return c })
+	if err != nil {
+		return fmt.Errorf("promptList: %w", err)
+	}
+	switch choice {
+	case 1:
+		if a.textMode {
+			fmt.Print("\nThe replay viewer needs the full-screen interface\n\n")
+			return nil
+		}
+		err = a.ViewReplay(ctx, path)
+	case 2:
+		err = a.AnalyzeReplay(path, "")
+	case 3:
+		var out string
+		out, err = promptPath("Save the report as: ")
+		if err != nil {
+			return fmt.Errorf("promptPath: %w", err)
+		}
+		err = a.AnalyzeReplay(path, out)
+	}
+	if err != nil {
+		fmt.Printf("\nCould not open the replay: %s\n\n", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+type numberInput struct {
+	id     uuid.UUID
+	txt    *tl.Text
+	prompt string
+	digits string
+	ch     chan<- int
+}
+
+// newNumberInput shows the digits typed so far and sends the number to ch
+// when Enter is pressed.
+func newNumberInput(x, y int, prompt string, ch chan<- int) *numberInput {
+	return &numberInput{
+		id:     uuid.New(),
+		txt:    tl.NewText(x, y, prompt, buttonFg, tl.ColorDefault),
+		prompt: prompt,
+		ch:     ch,
+	}
+}
+
+func (n *numberInput) ID() uuid.UUID {
+	return n.id
+}
+
+func (n *numberInput) Drawables() []tl.Drawable {
+	return []tl.Drawable{n}
+}
+
+func (n *numberInput) Draw(s *tl.Screen) {
+	n.txt.Draw(s)
+}
+
+func (n *numberInput) Tick(e tl.Event) {
+	if e.Type != tl.EventKey {
+		return
+	}
+	switch {
+	case e.Ch >= '0' && e.Ch <= '9' && len(n.digits) < 4:
+		n.digits += string(e.Ch)
+	case (e.Key == tl.KeyBackspace || e.Key == tl.KeyBackspace2) && n.digits != "":
+		n.digits = n.digits[:len(n.digits)-1]
+	case e.Key == tl.KeyEnter && n.digits != "":
+		number, _ := strconv.Atoi(n.digits)
+		n.digits = ""
+		select {
+		case n.ch <- number:
+		default:
+		}
+	}
+	n.txt.SetText(n.prompt + n.digits)
+}
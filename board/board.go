@@ -0,0 +1,206 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const Size = 10
+
+type Cell int
+
+const (
+	Unknown Cell = iota
+	Water
+	Hit
+	Sunk
+	// Occupied marks a cell holding a ship of a known layout, i.e. our own
+	Occupied
+	Excluded
+)
+
+func (c Cell) String() string {
+	switch c {
+	case Unknown:
+		return "unknown"
+	case Water:
+		return "water"
+	case Hit:
+		return "hit"
+	case Sunk:
+		return "sunk"
+	case Occupied:
+		return "ship"
+	case Excluded:
+		return "excluded"
+	}
+	return fmt.Sprintf("cell(%d)", int(c))
+}
+
+func (c Cell) Symbol() byte {
+	switch c {
+	case Water:
+		return '~'
+	case Hit:
+		return 'x'
+	case Sunk:
+		return '*'
+	case Occupied:
+		return '#'
+	case Excluded:
+		return '-'
+	}
+	return '.'
+}
+
+type Point struct {
+	X, Y int
+}
+
+func ParsePoint(coord string) (Point, error) {
+	if len(coord) < 2 || coord[0] < 'A' || coord[0] >= 'A'+Size {
+		return Point{}, fmt.Errorf("invalid coordinate %q", coord)
+	}
+	y, err := strconv.Atoi(coord[1:])
+	if err != nil || y < 1 || y > Size {
+		return Point{}, fmt.Errorf("invalid coordinate %q", coord)
+	}
+	return Point{int(coord[0] - 'A'), y - 1}, nil
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%c%d", p.X+'A', p.Y+1)
+}
+
+func (p Point) InBounds() bool {
+	return p.X >= 0 && p.X < Size && p.Y >= 0 && p.Y < Size
+}
+
+func (p Point) Neighbours() []Point {
+	var res []Point
+	for _, offset := range []Point{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+		if n := (Point{p.X + offset.X, p.Y + offset.Y}); n.InBounds() {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func (p Point) Surrounding() []Point {
+	var res []Point
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			n := Point{p.X + dx, p.Y + dy}
+			if (dx != 0 || dy != 0) && n.InBounds() {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+type Board [Size][Size]Cell
+
+func (b *Board) String() string {
+	var sb strings.Builder
+	sb.WriteString("  ")
+	for x := 0; x < Size; x++ {
+		fmt.Fprintf(&sb, " %c", 'A'+x)
+	}
+	for y := 0; y < Size; y++ {
+		fmt.Fprintf(&sb, "\n%2d", y+1)
+		for x := 0; x < Size; x++ {
+			fmt.Fprintf(&sb, " %c", b[x][y].Symbol())
+		}
+	}
+	return sb.String()
+}
+
+func (b *Board) At(p Point) Cell {
+	return b[p.X][p.Y]
+}
+
+func (b *Board) Set(p Point, c Cell) {
+	b[p.X][p.Y] = c
+}
+
+func (b *Board) Find(cells ...Cell) []Point {
+	var res []Point
+	for x := range b {
+		for y := range b[x] {
+			for _, c := range cells {
+				if b[x][y] == c {
+					res = append(res, Point{x, y})
+					break
+				}
+			}
+		}
+	}
+	return res
+}
+
+func (b *Board) Coords(cells ...Cell) []string {
+	var coords []string
+	for _, p := range b.Find(cells...) {
+		coords = append(coords, p.String())
+	}
+	return coords
+}
+
+func (b *Board) Component(start Point, cells ...Cell) []Point {
+	matches := func(p Point) bool {
+		for _, c := range cells {
+			if b.At(p) == c {
+				return true
+			}
+		}
+		return false
+	}
+	if !matches(start) {
+		return nil
+	}
+
+	component := []Point{start}
+	visited := map[Point]struct{}{start: {}}
+	for i := 0; i < len(component); i++ {
+		for _, n := range component[i].Neighbours() {
+			if _, ok := visited[n]; ok {
+				continue
+			}
+			visited[n] = struct{}{}
+			if matches(n) {
+				component = append(component, n)
+			}
+		}
+	}
+	return component
+}
+
+func (b *Board) Record(p Point, result string) Ship {
+	switch result {
+	case "miss":
+		b.Set(p, Water)
+	case "hit":
+		b.Set(p, Hit)
+	case "sunk":
+		b.Set(p, Hit)
+		return b.Sink(p)
+	}
+	return nil
+}
+
+func (b *Board) Sink(p Point) Ship {
+	ship := Ship(b.Component(p, Hit, Sunk))
+	for _, c := range ship {
+		b.Set(c, Sunk)
+	}
+	for _, c := range ship {
+		for _, n := range c.Surrounding() {
+			if b.At(n) == Unknown {
+				b.Set(n, Excluded)
+			}
+		}
+	}
+	return ship
+}
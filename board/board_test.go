@@ -0,0 +1,132 @@
+package board
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		coord   string
+		want    Point
+		wantErr bool
+	}{
+		{coord: "A1", want: Point{0, 0}},
+		{coord: "J10", want: Point{9, 9}},
+		{coord: "C7", want: Point{2, 6}},
+		{coord: "K1", wantErr: true},
+		{coord: "A0", wantErr: true},
+		{coord: "A11", wantErr: true},
+		{coord: "a1", wantErr: true},
+		{coord: "B", wantErr: true},
+		{coord: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.coord, func(t *testing.T) {
+			got, err := ParsePoint(tt.coord)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePoint(%q) err = %v, want error %v", tt.coord, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParsePoint(%q) = %v, want %v", tt.coord, got, tt.want)
+			}
+			if got.String() != tt.coord {
+				t.Errorf("ParsePoint(%q).String() = %q", tt.coord, got.String())
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		shots    []string
+		results  []string
+		wantSunk Ship
+		want     map[string]Cell
+	}{
+		{
+			name:    "miss",
+			shots:   []string{"B2"},
+			results: []string{"miss"},
+			want:    map[string]Cell{"B2": Water, "B3": Unknown},
+		},
+		{
+			name:    "hit",
+			shots:   []string{"B2"},
+			results: []string{"hit"},
+			want:    map[string]Cell{"B2": Hit, "B3": Unknown},
+		},
+		{
+			name:     "sunk after hits",
+			shots:    []string{"B2", "B3", "C3"},
+			results:  []string{"hit", "hit", "sunk"},
+			wantSunk: Ship{{1, 1}, {1, 2}, {2, 2}},
+			want:     map[string]Cell{"B2": Sunk, "B3": Sunk, "C3": Sunk, "A1": Excluded, "D4": Excluded, "C2": Excluded, "E5": Unknown},
+		},
+		{
+			name:     "sunk in the corner",
+			shots:    []string{"J10"},
+			results:  []string{"sunk"},
+			wantSunk: Ship{{9, 9}},
+			want:     map[string]Cell{"J10": Sunk, "I9": Excluded, "I10": Excluded, "J9": Excluded, "H8": Unknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Board
+			var sunk Ship
+			for i, coord := range tt.shots {
+				p, err := ParsePoint(coord)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sunk = b.Record(p, tt.results[i])
+			}
+			sort.Slice(sunk, func(i, j int) bool {
+				return sunk[i].X < sunk[j].X || sunk[i].X == sunk[j].X && sunk[i].Y < sunk[j].Y
+			})
+			if !reflect.DeepEqual(sunk, tt.wantSunk) {
+				t.Errorf("sunk ship = %v, want %v", sunk, tt.wantSunk)
+			}
+			for coord, want := range tt.want {
+				p, _ := ParsePoint(coord)
+				if got := b.At(p); got != want {
+					t.Errorf("%s is %s, want %s", coord, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutFire(t *testing.T) {
+	l := NewLayout([]Ship{{{0, 0}, {1, 0}}, {{5, 5}}})
+
+	tests := []struct {
+		coord string
+		want  string
+		sunk  Ship
+	}{
+		{"A1", "hit", nil},
+		{"C1", "miss", nil},
+		{"B1", "sunk", Ship{{0, 0}, {1, 0}}},
+		{"F6", "sunk", Ship{{5, 5}}},
+	}
+	for i, tt := range tests {
+		if l.Destroyed() {
+			t.Fatalf("the layout is destroyed after %d shots", i)
+		}
+		p, _ := ParsePoint(tt.coord)
+		got, sunk := l.Fire(p)
+		if got != tt.want || !reflect.DeepEqual(sunk, tt.sunk) {
+			t.Errorf("Fire(%s) = %s, %v, want %s, %v", tt.coord, got, sunk, tt.want, tt.sunk)
+		}
+	}
+	if !l.Destroyed() {
+		t.Error("the layout is not destroyed after sinking every ship")
+	}
+}
@@ -0,0 +1,163 @@
+package board
+
+import (
+	"fmt"
+)
+
+// Editor holds a layout that is being built cell by cell, with undo and redo.
+// Ships are the orthogonally connected groups of occupied cells.
+type Editor struct {
+	board Board
+	undo  []Board
+	redo  []Board
+}
+
+func NewEditor(coords []string) (*Editor, error) {
+	e := &Editor{}
+	for _, c := range coords {
+		p, err := ParsePoint(c)
+		if err != nil {
+			return nil, err
+		}
+		e.board.Set(p, Occupied)
+	}
+	return e, nil
+}
+
+func (e *Editor) Board() Board {
+	return e.board
+}
+
+func (e *Editor) Coords() []string {
+	return e.board.Coords(Occupied)
+}
+
+func (e *Editor) Validate() error {
+	_, err := Validate(e.Coords())
+	return err
+}
+
+func (e *Editor) ShipAt(p Point) Ship {
+	return e.board.Component(p, Occupied)
+}
+
+func (e *Editor) Toggle(p Point) {
+	e.save()
+	if e.board.At(p) == Occupied {
+		e.board.Set(p, Unknown)
+	} else {
+		e.board.Set(p, Occupied)
+	}
+}
+
+func (e *Editor) Remove(ship Ship) {
+	e.save()
+	for _, p := range ship {
+		e.board.Set(p, Unknown)
+	}
+}
+
+func (e *Editor) Replace(coords []string) error {
+	next, err := NewEditor(coords)
+	if err != nil {
+		return err
+	}
+	e.save()
+	e.board = next.board
+	return nil
+}
+
+// Move shifts ship so that its cell from lands on to.
+func (e *Editor) Move(ship Ship, from, to Point) (Ship, error) {
+	return e.transform(ship, func(p Point) Point {
+		return Point{p.X + to.X - from.X, p.Y + to.Y - from.Y}
+	})
+}
+
+// Rotate turns ship by 90 degrees clockwise, keeping its top left corner.
+func (e *Editor) Rotate(ship Ship) (Ship, error) {
+	return e.anchored(ship, func(p Point) Point { return Point{-p.Y, p.X} })
+}
+
+// Mirror flips ship horizontally, keeping its top left corner.
+func (e *Editor) Mirror(ship Ship) (Ship, error) {
+	return e.anchored(ship, func(p Point) Point { return Point{-p.X, p.Y} })
+}
+
+func (e *Editor) anchored(ship Ship, f func(Point) Point) (Ship, error) {
+	before, after := corner(ship), corner(mapShip(ship, f))
+	return e.transform(ship, func(p Point) Point {
+		p = f(p)
+		return Point{p.X - after.X + before.X, p.Y - after.Y + before.Y}
+	})
+}
+
+func (e *Editor) transform(ship Ship, f func(Point) Point) (Ship, error) {
+	moved := mapShip(ship, f)
+
+	rest := e.board
+	for _, p := range ship {
+		rest.Set(p, Unknown)
+	}
+	for _, p := range moved {
+		if !p.InBounds() {
+			return ship, fmt.Errorf("ship would leave the board")
+		}
+		for _, n := range append(p.Surrounding(), p) {
+			if rest.At(n) == Occupied {
+				return ship, fmt.Errorf("ship would touch another ship at %s", n)
+			}
+		}
+	}
+
+	e.save()
+	for _, p := range moved {
+		rest.Set(p, Occupied)
+	}
+	e.board = rest
+	return moved, nil
+}
+
+func (e *Editor) Undo() bool {
+	if len(e.undo) == 0 {
+		return false
+	}
+	e.redo = append(e.redo, e.board)
+	e.board, e.undo = e.undo[len(e.undo)-1], e.undo[:len(e.undo)-1]
+	return true
+}
+
+func (e *Editor) Redo() bool {
+	if len(e.redo) == 0 {
+		return false
+	}
+	e.undo = append(e.undo, e.board)
+	e.board, e.redo = e.redo[len(e.redo)-1], e.redo[:len(e.redo)-1]
+	return true
+}
+
+func (e *Editor) save() {
+	e.undo = append(e.undo, e.board)
+	e.redo = nil
+}
+
+func mapShip(ship Ship, f func(Point) Point) Ship {
+	res := make(Ship, len(ship))
+	for i, p := range ship {
+		res[i] = f(p)
+	}
+	return res
+}
+
+func corner(ship Ship) Point {
+	c := ship[0]
+	for _, p := range ship {
+		if p.X < c.X {
+			c.X = p.X
+		}
+		if p.Y < c.Y {
+			c.Y = p.Y
+		}
+	}
+	return c
+}
@@ -0,0 +1,197 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestEditor(t *testing.T, coords ...string) *Editor {
+	t.Helper()
+	e, err := NewEditor(coords)
+	if err != nil {
+		t.Fatalf("NewEditor: %v", err)
+	}
+	return e
+}
+
+func at(coord string) Point {
+	p, _ := ParsePoint(coord)
+	return p
+}
+
+func TestEditorTransforms(t *testing.T) {
+	tests := []struct {
+		name    string
+		coords  []string
+		ship    string
+		op      func(e *Editor, ship Ship) (Ship, error)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "move",
+			coords: []string{"A1", "B1", "C1"},
+			ship:   "A1",
+			op:     func(e *Editor, s Ship) (Ship, error) { return e.Move(s, at("A1"), at("A5")) },
+			want:   []string{"A5", "B5", "C5"},
+		},
+		{
+			name:   "move over its own cells",
+			coords: []string{"A1", "B1", "C1"},
+			ship:   "B1",
+			op:     func(e *Editor, s Ship) (Ship, error) { return e.Move(s, at("B1"), at("C1")) },
+			want:   []string{"B1", "C1", "D1"},
+		},
+		{
+			name:    "move off the edge",
+			coords:  []string{"A1", "B1", "C1"},
+			ship:    "A1",
+			op:      func(e *Editor, s Ship) (Ship, error) { return e.Move(s, at("A1"), at("I1")) },
+			wantErr: true,
+		},
+		{
+			name:    "move next to another ship",
+			coords:  []string{"A1", "B1", "C1", "E5"},
+			ship:    "E5",
+			op:      func(e *Editor, s Ship) (Ship, error) { return e.Move(s, at("E5"), at("D2")) },
+			wantErr: true,
+		},
+		{
+			name:   "rotate",
+			coords: []string{"A1", "B1", "C1"},
+			ship:   "C1",
+			op:     (*Editor).Rotate,
+			want:   []string{"A1", "A2", "A3"},
+		},
+		{
+			name:   "rotate an l-shape",
+			coords: []string{"C3", "D3", "E3", "E4"},
+			ship:   "C3",
+			op:     (*Editor).Rotate,
+			want:   []string{"C5", "D3", "D4", "D5"},
+		},
+		{
+			name:    "rotate off the bottom edge",
+			coords:  []string{"A10", "B10", "C10"},
+			ship:    "A10",
+			op:      (*Editor).Rotate,
+			wantErr: true,
+		},
+		{
+			name:    "rotate off the right edge",
+			coords:  []string{"J1", "J2", "J3"},
+			ship:    "J2",
+			op:      (*Editor).Rotate,
+			wantErr: true,
+		},
+		{
+			name:    "rotate next to another ship",
+			coords:  []string{"A1", "B1", "C1", "B4"},
+			ship:    "A1",
+			op:      (*Editor).Rotate,
+			wantErr: true,
+		},
+		{
+			name:   "mirror",
+			coords: []string{"A1", "B1", "C1", "C2"},
+			ship:   "A1",
+			op:     (*Editor).Mirror,
+			want:   []string{"A1", "A2", "B1", "C1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEditor(t, tt.coords...)
+			ship := e.ShipAt(at(tt.ship))
+			before := e.Coords()
+
+			moved, err := tt.op(e, ship)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("the ship moved to %v, want an error", moved)
+				}
+				if !reflect.DeepEqual(e.Coords(), before) || !reflect.DeepEqual(moved, ship) {
+					t.Errorf("after the error the board is %v and the ship %v, want them unchanged", e.Coords(), moved)
+				}
+				if e.Undo() {
+					t.Error("the failed change can be undone")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			var cells []string
+			for _, p := range moved {
+				cells = append(cells, p.String())
+			}
+			if !reflect.DeepEqual(sorted(cells), tt.want) {
+				t.Errorf("the ship moved to %v, want %v", sorted(cells), tt.want)
+			}
+			b := e.Board()
+			for _, c := range tt.want {
+				if b.At(at(c)) != Occupied {
+					t.Errorf("%s is not occupied after the change", c)
+				}
+			}
+			if !e.Undo() || !reflect.DeepEqual(e.Coords(), before) {
+				t.Errorf("after an undo the board is %v, want %v", e.Coords(), before)
+			}
+		})
+	}
+}
+
+func TestEditorUndoRedo(t *testing.T) {
+	e := newTestEditor(t, "A1", "B1")
+	if e.Undo() || e.Redo() {
+		t.Fatal("a new editor has history")
+	}
+
+	e.Toggle(at("E5"))
+	e.Toggle(at("G7"))
+	if _, err := e.Move(e.ShipAt(at("A1")), at("A1"), at("J1")); err == nil {
+		t.Fatal("the ship was moved off the board")
+	}
+
+	steps := []struct {
+		name string
+		do   func() bool
+		ok   bool
+		want []string
+	}{
+		{"undo skips the failed move", e.Undo, true, []string{"A1", "B1", "E5"}},
+		{"undo", e.Undo, true, []string{"A1", "B1"}},
+		{"nothing more to undo", e.Undo, false, []string{"A1", "B1"}},
+		{"failed move keeps redo", func() bool {
+			_, err := e.Move(e.ShipAt(at("A1")), at("A1"), at("J10"))
+			return err == nil
+		}, false, []string{"A1", "B1"}},
+		{"redo", e.Redo, true, []string{"A1", "B1", "E5"}},
+		{"redo again", e.Redo, true, []string{"A1", "B1", "E5", "G7"}},
+		{"undo before a new change", e.Undo, true, []string{"A1", "B1", "E5"}},
+		{"rotate", func() bool {
+			_, err := e.Rotate(e.ShipAt(at("A1")))
+			return err == nil
+		}, true, []string{"A1", "A2", "E5"}},
+		{"new change clears redo", e.Redo, false, []string{"A1", "A2", "E5"}},
+		{"undo the rotation", e.Undo, true, []string{"A1", "B1", "E5"}},
+		{"remove a ship", func() bool {
+			e.Remove(e.ShipAt(at("B1")))
+			return true
+		}, true, []string{"E5"}},
+		{"replace with an invalid coordinate", func() bool {
+			return e.Replace([]string{"A1", "K1"}) == nil
+		}, false, []string{"E5"}},
+		{"undo the removal", e.Undo, true, []string{"A1", "B1", "E5"}},
+		{"redo the removal", e.Redo, true, []string{"E5"}},
+	}
+	for _, s := range steps {
+		if ok := s.do(); ok != s.ok {
+			t.Fatalf("%s: returned %v, want %v", s.name, ok, s.ok)
+		}
+		if got := sorted(e.Coords()); !reflect.DeepEqual(got, s.want) {
+			t.Fatalf("%s: the board is %v, want %v", s.name, got, s.want)
+		}
+	}
+}
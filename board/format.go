@@ -0,0 +1,185 @@
+package board
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatGrid   = "grid"
+	FormatJSON   = "json"
+	FormatCoords = "coords"
+)
+
+type jsonLayout struct {
+	Coords []string `json:"coords"`
+}
+
+// Formats lists the supported formats.
+var Formats = []string{FormatGrid, FormatJSON, FormatCoords}
+
+// FormatFor suggests a format for path by its extension.
+func FormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".txt", ".grid":
+		return FormatGrid
+	}
+	return FormatCoords
+}
+
+func Encode(w io.Writer, coords []string, format string) error {
+	switch format {
+	case FormatGrid:
+		var b Board
+		for _, c := range coords {
+			p, err := ParsePoint(c)
+			if err != nil {
+				return err
+			}
+			b.Set(p, Occupied)
+		}
+		_, err := fmt.Fprintln(w, b.String())
+		return err
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonLayout{Coords: coords})
+	case FormatCoords:
+		_, err := fmt.Fprintln(w, strings.Join(coords, "\n"))
+		return err
+	}
+	return fmt.Errorf("unknown board format %q", format)
+}
+
+// Decode reads coordinates in the given format, an empty format guesses it
+// from the content.
+func Decode(r io.Reader, format string) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if format == "" {
+		format = detectFormat(data)
+	}
+
+	switch format {
+	case FormatGrid:
+		return decodeGrid(data)
+	case FormatJSON:
+		var l jsonLayout
+		err = json.Unmarshal(data, &l)
+		if err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		return l.Coords, nil
+	case FormatCoords:
+		var coords []string
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line, _, _ := strings.Cut(scanner.Text(), "#")
+			coords = append(coords, strings.FieldsFunc(line, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})...)
+		}
+		return coords, scanner.Err()
+	}
+	return nil, fmt.Errorf("unknown board format %q", format)
+}
+
+// detectFormat tells the format by the content, whatever the extension of
+// the file. Coordinates start with a letter and grid rows with a number.
+func detectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatJSON
+	case bytes.HasPrefix(trimmed, []byte("A B C")), len(trimmed) > 0 && trimmed[0] >= '0' && trimmed[0] <= '9':
+		return FormatGrid
+	}
+	return FormatCoords
+}
+
+func decodeGrid(data []byte) ([]string, error) {
+	var coords []string
+	rows := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "A" {
+			continue
+		}
+
+		y, err := strconv.Atoi(fields[0])
+		if err != nil || y < 1 || y > Size {
+			return nil, fmt.Errorf("invalid grid row %q", scanner.Text())
+		}
+		if len(fields) != Size+1 {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", y, len(fields)-1, Size)
+		}
+		for x, cell := range fields[1:] {
+			switch cell {
+			case "#", "x", "X":
+				coords = append(coords, Point{x, y - 1}.String())
+			case ".", "~", "-":
+			default:
+				return nil, fmt.Errorf("invalid cell %q in row %d", cell, y)
+			}
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rows != Size {
+		return nil, fmt.Errorf("grid has %d rows, expected %d", rows, Size)
+	}
+	return coords, nil
+}
+
+// LoadFile reads a layout from path in the format detected from its content
+// and validates it.
+func LoadFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer f.Close()
+
+	coords, err := Decode(f, "")
+	if err != nil {
+		return nil, fmt.Errorf("board.Decode: %w", err)
+	}
+	_, err = Validate(coords)
+	if err != nil {
+		return nil, err
+	}
+	return coords, nil
+}
+
+// SaveFile writes coords to path in the given format, an empty format is
+// picked by the extension of path.
+func SaveFile(path string, coords []string, format string) error {
+	if format == "" {
+		format = FormatFor(path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create: %w", err)
+	}
+
+	err = Encode(f, coords, format)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("board.Encode: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,128 @@
+package board
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func sorted(coords []string) []string {
+	res := append([]string(nil), coords...)
+	sort.Strings(res)
+	return res
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Encode(&buf, validCoords, format)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			for _, decodeAs := range []string{format, ""} {
+				got, err := Decode(bytes.NewReader(buf.Bytes()), decodeAs)
+				if err != nil {
+					t.Fatalf("Decode(%q): %v", decodeAs, err)
+				}
+				if !reflect.DeepEqual(sorted(got), sorted(validCoords)) {
+					t.Errorf("Decode(%q) = %v, want %v", decodeAs, got, validCoords)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	var b Board
+	b.Set(Point{0, 0}, Occupied)
+	grid := b.String()
+	headless := grid[strings.Index(grid, "\n")+1:]
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"json", `  {"coords": ["A1"]}`, FormatJSON},
+		{"grid", grid, FormatGrid},
+		{"grid without the header", headless, FormatGrid},
+		{"coordinates", "A1, B1\n# a comment\nC1\n", FormatCoords},
+		{"coordinates after blank lines", "\n\n  J10 A1", FormatCoords},
+		{"empty", "", FormatCoords},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("detectFormat(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var b Board
+	grid := b.String()
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{"missing row", grid[:strings.LastIndex(grid, "\n")], FormatGrid},
+		{"invalid cell", strings.Replace(grid, ".", "?", 1), FormatGrid},
+		{"short row", strings.Replace(grid, " . .\n", " .\n", 1), FormatGrid},
+		{"broken json", `{"coords": [`, FormatJSON},
+		{"unknown format", "A1", "yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if coords, err := Decode(strings.NewReader(tt.data), tt.format); err == nil {
+				t.Errorf("Decode = %v, want an error", coords)
+			}
+		})
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file       string
+		format     string
+		wantPrefix string
+	}{
+		{"list.txt", FormatCoords, "A1\n"},
+		{"grid.txt", FormatGrid, "   A B C"},
+		{"layout.json", "", "{"},
+		{"layout.grid", "", "   A B C"},
+		{"layout", "", "A1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			err := SaveFile(path, validCoords, tt.format)
+			if err != nil {
+				t.Fatalf("SaveFile: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.wantPrefix) {
+				t.Errorf("%s starts with %q, want %q", tt.file, data[:10], tt.wantPrefix)
+			}
+
+			coords, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if !reflect.DeepEqual(sorted(coords), sorted(validCoords)) {
+				t.Errorf("LoadFile = %v, want %v", coords, validCoords)
+			}
+		})
+	}
+}
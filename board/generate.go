@@ -0,0 +1,169 @@
+package board
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const DefaultStyle = "random"
+
+// weight scores a candidate placement given the ships already on the board,
+// higher weights are picked more often.
+type weight func(ship Ship, placed []Ship) float64
+
+var styles = map[string]weight{
+	DefaultStyle: func(Ship, []Ship) float64 { return 1 },
+	"edges": func(ship Ship, _ []Ship) float64 {
+		return math.Pow(4, float64(edgeCells(ship)))
+	},
+	"clustered": func(ship Ship, placed []Ship) float64 {
+		if len(placed) == 0 {
+			return 1
+		}
+		return math.Pow(0.05, float64(distance(ship, placed)-2))
+	},
+	"spread": func(ship Ship, placed []Ship) float64 {
+		if len(placed) == 0 {
+			return 1
+		}
+		return math.Pow(4, float64(distance(ship, placed)-2))
+	},
+	"irregular": func(ship Ship, _ []Ship) float64 {
+		if isLine(ship) {
+			return 1
+		}
+		return 20
+	},
+}
+
+func Styles() []string {
+	var names []string
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate returns a random legal layout of the standard fleet placed in the
+// given style. The same rand state always gives the same layout.
+func Generate(style string, r *rand.Rand) (*Layout, error) {
+	w, ok := styles[style]
+	if !ok {
+		return nil, fmt.Errorf("unknown placement style %q", style)
+	}
+	for {
+		if ships, ok := tryGenerate(w, r); ok {
+			return NewLayout(ships), nil
+		}
+	}
+}
+
+func RandomLayout(r *rand.Rand) *Layout {
+	l, _ := Generate(DefaultStyle, r)
+	return l
+}
+
+func tryGenerate(w weight, r *rand.Rand) ([]Ship, bool) {
+	var ships []Ship
+	var blocked Board
+
+	for length := 4; length >= 1; length-- {
+		for s := 0; s < StandardFleet()[length]; s++ {
+			var candidates []Ship
+			var weights []float64
+			total := 0.0
+			for x := 0; x < Size; x++ {
+				for y := 0; y < Size; y++ {
+					for _, shape := range Shapes[length] {
+						ship, ok := place(&blocked, shape, Point{x, y})
+						if !ok {
+							continue
+						}
+						candidates = append(candidates, ship)
+						weights = append(weights, w(ship, ships))
+						total += weights[len(weights)-1]
+					}
+				}
+			}
+			if len(candidates) == 0 {
+				return nil, false
+			}
+
+			pick := r.Float64() * total
+			i := 0
+			for ; i < len(candidates)-1 && pick >= weights[i]; i++ {
+				pick -= weights[i]
+			}
+
+			ship := candidates[i]
+			for _, p := range ship {
+				blocked.Set(p, Occupied)
+				for _, n := range p.Surrounding() {
+					blocked.Set(n, Occupied)
+				}
+			}
+			ships = append(ships, ship)
+		}
+	}
+	return ships, true
+}
+
+func place(blocked *Board, shape []Point, origin Point) (Ship, bool) {
+	ship := make(Ship, 0, len(shape))
+	for _, offset := range shape {
+		p := Point{origin.X + offset.X, origin.Y + offset.Y}
+		if !p.InBounds() || blocked.At(p) != Unknown {
+			return nil, false
+		}
+		ship = append(ship, p)
+	}
+	return ship, true
+}
+
+func edgeCells(ship Ship) int {
+	n := 0
+	for _, p := range ship {
+		if p.X == 0 || p.Y == 0 || p.X == Size-1 || p.Y == Size-1 {
+			n++
+		}
+	}
+	return n
+}
+
+// distance is the smallest Chebyshev distance between ship and any placed cell.
+func distance(ship Ship, placed []Ship) int {
+	min := 2 * Size
+	for _, p := range ship {
+		for _, other := range placed {
+			for _, q := range other {
+				d := abs(p.X - q.X)
+				if dy := abs(p.Y - q.Y); dy > d {
+					d = dy
+				}
+				if d < min {
+					min = d
+				}
+			}
+		}
+	}
+	return min
+}
+
+func isLine(ship Ship) bool {
+	sameX, sameY := true, true
+	for _, p := range ship[1:] {
+		sameX = sameX && p.X == ship[0].X
+		sameY = sameY && p.Y == ship[0].Y
+	}
+	return sameX || sameY
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,70 @@
+package board
+
+type Layout struct {
+	ships []Ship
+	cells map[Point]int
+	hits  map[Point]struct{}
+}
+
+func NewLayout(ships []Ship) *Layout {
+	l := &Layout{
+		ships: ships,
+		cells: make(map[Point]int),
+		hits:  make(map[Point]struct{}),
+	}
+	for i, ship := range ships {
+		for _, p := range ship {
+			l.cells[p] = i
+		}
+	}
+	return l
+}
+
+func ParseLayout(coords []string) (*Layout, error) {
+	ships, err := Validate(coords)
+	if err != nil {
+		return nil, err
+	}
+	return NewLayout(ships), nil
+}
+
+func (l *Layout) Ships() []Ship {
+	return l.ships
+}
+
+func (l *Layout) Fire(p Point) (string, Ship) {
+	i, ok := l.cells[p]
+	if !ok {
+		return "miss", nil
+	}
+
+	l.hits[p] = struct{}{}
+	for _, c := range l.ships[i] {
+		if _, hit := l.hits[c]; !hit {
+			return "hit", nil
+		}
+	}
+	return "sunk", l.ships[i]
+}
+
+func (l *Layout) Destroyed() bool {
+	return len(l.hits) == len(l.cells)
+}
+
+func (l *Layout) Board() Board {
+	var b Board
+	for p := range l.cells {
+		b.Set(p, Occupied)
+	}
+	return b
+}
+
+func (l *Layout) Coords() []string {
+	var coords []string
+	for _, ship := range l.ships {
+		for _, p := range ship {
+			coords = append(coords, p.String())
+		}
+	}
+	return coords
+}
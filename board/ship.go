@@ -0,0 +1,123 @@
+package board
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var Shapes = map[int][][]Point{
+	4: {
+		// lines
+		{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+		{{0, 0}, {0, 1}, {0, 2}, {0, 3}},
+		// l-shape mirrored
+		{{0, 0}, {1, 0}, {2, 0}, {2, 1}},
+		{{0, 0}, {0, 1}, {0, 2}, {-1, 2}},
+		{{0, 0}, {0, 1}, {1, 1}, {2, 1}},
+		{{0, 0}, {1, 0}, {0, 1}, {0, 2}},
+		// l-shape
+		{{0, 0}, {1, 0}, {2, 0}, {0, 1}},
+		{{0, 0}, {1, 0}, {1, 1}, {1, 2}},
+		{{0, 0}, {1, 0}, {2, 0}, {2, -1}},
+		{{0, 0}, {0, 1}, {0, 2}, {1, 2}},
+		// t-shape
+		{{0, 0}, {1, 0}, {2, 0}, {1, 1}},
+		{{0, 0}, {0, 1}, {0, 2}, {-1, 1}},
+		{{0, 0}, {1, 0}, {2, 0}, {1, -1}},
+		{{0, 0}, {0, 1}, {0, 2}, {1, 1}},
+		// square
+		{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	},
+	3: {
+		// lines
+		{{0, 0}, {1, 0}, {2, 0}},
+		{{0, 0}, {0, 1}, {0, 2}},
+		// corner-missing square
+		{{0, 0}, {0, 1}, {1, 1}},
+		{{0, 0}, {0, 1}, {1, 0}},
+		{{0, 0}, {1, 0}, {1, 1}},
+		{{0, 0}, {0, 1}, {-1, 1}},
+	},
+	2: {
+		{{0, 0}, {1, 0}},
+		{{0, 0}, {0, 1}},
+	},
+	1: {
+		{{0, 0}},
+	},
+}
+
+type Ship []Point
+
+func (s Ship) Contains(p Point) bool {
+	for _, c := range s {
+		if c == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Ship) String() string {
+	var cells []string
+	for _, p := range s {
+		cells = append(cells, p.String())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(cells, " "))
+}
+
+func (s Ship) ValidShape() bool {
+	key := shapeKey(s)
+	for _, shape := range Shapes[len(s)] {
+		if shapeKey(shape) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func shapeKey(ship []Point) string {
+	if len(ship) == 0 {
+		return ""
+	}
+	minX, minY := ship[0].X, ship[0].Y
+	for _, p := range ship {
+		if p.X < minX {
+			minX = p.X
+		}
+		if p.Y < minY {
+			minY = p.Y
+		}
+	}
+
+	var cells []string
+	for _, p := range ship {
+		cells = append(cells, fmt.Sprintf("%d,%d", p.X-minX, p.Y-minY))
+	}
+	sort.Strings(cells)
+	return fmt.Sprint(cells)
+}
+
+// Fleet maps ship length to the number of ships of that length.
+type Fleet map[int]int
+
+func StandardFleet() Fleet {
+	return Fleet{4: 1, 3: 2, 2: 3, 1: 4}
+}
+
+func (f Fleet) Clone() Fleet {
+	clone := make(Fleet, len(f))
+	for length, count := range f {
+		clone[length] = count
+	}
+	return clone
+}
+
+func (f Fleet) Ships() int {
+	total := 0
+	for _, count := range f {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,104 @@
+package board
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Violation struct {
+	Cells   []Point
+	Message string
+}
+
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var msgs []string
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Message)
+	}
+	return fmt.Sprintf("invalid layout: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) add(msg string, cells ...Point) {
+	e.Violations = append(e.Violations, Violation{Cells: cells, Message: msg})
+}
+
+// Validate splits coords into ships and checks them against the standard
+// fleet, the allowed shapes and the no-touch rule. All violations are
+// reported at once in a *ValidationError.
+func Validate(coords []string) ([]Ship, error) {
+	verr := &ValidationError{}
+	var b Board
+	for _, c := range coords {
+		p, err := ParsePoint(c)
+		if err != nil {
+			verr.add(err.Error())
+			continue
+		}
+		if b.At(p) == Occupied {
+			verr.add(fmt.Sprintf("duplicate coordinate %s", p), p)
+			continue
+		}
+		b.Set(p, Occupied)
+	}
+
+	var ships []Ship
+	owner := make(map[Point]int)
+	for _, p := range b.Find(Occupied) {
+		if _, ok := owner[p]; ok {
+			continue
+		}
+		ship := Ship(b.Component(p, Occupied))
+		sort.Slice(ship, func(i, j int) bool {
+			return ship[i].X < ship[j].X || ship[i].X == ship[j].X && ship[i].Y < ship[j].Y
+		})
+		for _, c := range ship {
+			owner[c] = len(ships)
+		}
+		ships = append(ships, ship)
+	}
+
+	counts := make(Fleet)
+	for _, ship := range ships {
+		switch {
+		case len(ship) > 4:
+			verr.add(fmt.Sprintf("ship %s has %d cells, at most 4 are allowed", ship, len(ship)), ship...)
+		case !ship.ValidShape():
+			verr.add(fmt.Sprintf("ship %s has a shape that is not allowed", ship), ship...)
+		default:
+			counts[len(ship)]++
+		}
+	}
+	for length := 4; length >= 1; length-- {
+		if want := StandardFleet()[length]; counts[length] != want {
+			verr.add(fmt.Sprintf("expected %d ship(s) of length %d, got %d", want, length, counts[length]))
+		}
+	}
+
+	reported := make(map[[2]int]struct{})
+	for _, p := range b.Find(Occupied) {
+		for _, n := range p.Surrounding() {
+			i, j := owner[p], owner[n]
+			if b.At(n) != Occupied || i == j {
+				continue
+			}
+			if i > j {
+				i, j = j, i
+			}
+			if _, ok := reported[[2]int{i, j}]; ok {
+				continue
+			}
+			reported[[2]int{i, j}] = struct{}{}
+			verr.add(fmt.Sprintf("ships %s and %s touch diagonally at %s and %s", ships[i], ships[j], p, n), p, n)
+		}
+	}
+
+	if len(verr.Violations) > 0 {
+		return nil, verr
+	}
+	return ships, nil
+}
@@ -0,0 +1,134 @@
+package board
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var validCoords = []string{
+	"A1", "B1", "C1", "D1",
+	"F1", "G1", "H1",
+	"A3", "A4", "A5",
+	"C3", "D3",
+	"J1", "J2",
+	"F3", "F4",
+	"H3", "C6", "E8", "J9",
+}
+
+func without(coords []string, drop ...string) []string {
+	var res []string
+outer:
+	for _, c := range coords {
+		for _, d := range drop {
+			if c == d {
+				continue outer
+			}
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		coords []string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			coords: validCoords,
+		},
+		{
+			name:   "duplicate coordinate",
+			coords: append(append([]string{}, validCoords...), "C6"),
+			want:   []string{"duplicate coordinate C6"},
+		},
+		{
+			name:   "invalid coordinate",
+			coords: append(append([]string{}, validCoords...), "K1"),
+			want:   []string{`invalid coordinate "K1"`},
+		},
+		{
+			name:   "missing ship",
+			coords: without(validCoords, "J9"),
+			want:   []string{"expected 4 ship(s) of length 1, got 3"},
+		},
+		{
+			name:   "ships touching diagonally",
+			coords: append(without(validCoords, "J9"), "B7"),
+			want:   []string{"ships [B7] and [C6] touch diagonally at B7 and C6"},
+		},
+		{
+			name:   "shape not allowed",
+			coords: append(without(validCoords, "A1", "B1", "C1", "D1", "E8"), "F8", "G8", "G9", "H9", "A10"),
+			want:   []string{"has a shape that is not allowed", "expected 1 ship(s) of length 4, got 0"},
+		},
+		{
+			name:   "ship too long",
+			coords: append(without(validCoords, "H3"), "E1"),
+			want: []string{
+				"has 8 cells, at most 4 are allowed",
+				"expected 1 ship(s) of length 4, got 0",
+				"expected 2 ship(s) of length 3, got 1",
+				"expected 4 ship(s) of length 1, got 3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ships, err := Validate(tt.coords)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				if len(ships) != StandardFleet().Ships() {
+					t.Errorf("Validate returned %d ships, want %d", len(ships), StandardFleet().Ships())
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate err = %v, want a *ValidationError", err)
+			}
+			if len(verr.Violations) != len(tt.want) {
+				t.Fatalf("Validate err = %v, want %d violations", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(verr.Violations[i].Message, want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, verr.Violations[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratedLayoutsAreValid(t *testing.T) {
+	for _, style := range Styles() {
+		t.Run(style, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				layout, err := Generate(style, rand.New(rand.NewSource(seed)))
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				coords := layout.Coords()
+				if _, err := Validate(coords); err != nil {
+					t.Errorf("seed %d: %v", seed, err)
+				}
+
+				again, _ := Generate(style, rand.New(rand.NewSource(seed)))
+				if !reflect.DeepEqual(again.Coords(), coords) {
+					t.Errorf("seed %d gave %v and then %v", seed, coords, again.Coords())
+				}
+			}
+		})
+	}
+
+	if _, err := Generate("diagonal", rand.New(rand.NewSource(1))); err == nil {
+		t.Error("Generate accepted an unknown style")
+	}
+}
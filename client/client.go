@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/charmbracelet/log"
@@ -9,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,8 +21,22 @@ var (
 	ErrServiceUnavailable = fmt.Errorf("service unavailable")
 	ErrNotFound           = fmt.Errorf("not found")
 	ErrBadRequest         = fmt.Errorf("bad request")
+	ErrConflict           = fmt.Errorf("conflict")
+	ErrTooManyRequests    = fmt.Errorf("too many requests")
+	ErrInternalServer     = fmt.Errorf("internal server error")
 )
 
+var sentinels = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusTooManyRequests:     ErrTooManyRequests,
+	http.StatusInternalServerError: ErrInternalServer,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+}
+
 type Client struct {
 	*http.Client
 	baseUrl string
@@ -35,8 +52,16 @@ func NewClient(baseUrl string, timeout time.Duration) *Client {
 	}
 }
 
-func (c *Client) newRequestWithToken(method string, path string, body io.Reader) (*http.Request, error) {
-	req, err := c.newRequest(method, path, body)
+func (c *Client) Token() string {
+	return c.token
+}
+
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+func (c *Client) newRequestWithToken(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	req, err := c.newRequest(ctx, method, path, body)
 	if err != nil {
 		return nil, fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -46,21 +71,21 @@ func (c *Client) newRequestWithToken(method string, path string, body io.Reader)
 	return req, nil
 }
 
-func (c *Client) newRequest(method string, path string, body io.Reader) (*http.Request, error) {
+func (c *Client) newRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
 	path, err := url.JoinPath(c.baseUrl, path)
 	if err != nil {
 		return nil, fmt.Errorf("url.JoinPath: %w", err)
 	}
 
-	req, err := http.NewRequest(method, path, body)
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
-		return nil, fmt.Errorf("http.newRequest: %w", err)
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
 	}
 
 	return req, nil
 }
 
-func (c *Client) InitGame(payload models.GamePayload) error {
+func (c *Client) InitGame(ctx context.Context, payload models.GamePayload) error {
 	payloadJson, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("json.Marshal: %w", err)
@@ -68,7 +93,7 @@ func (c *Client) InitGame(payload models.GamePayload) error {
 
 	payloadReader := bytes.NewReader(payloadJson)
 
-	req, err := c.newRequest(http.MethodPost, "/game", payloadReader)
+	req, err := c.newRequest(ctx, http.MethodPost, "/game", payloadReader)
 	if err != nil {
 		return fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -79,9 +104,10 @@ func (c *Client) InitGame(payload models.GamePayload) error {
 	if err != nil {
 		return fmt.Errorf("client.Do: %w", err)
 	}
+	defer res.Body.Close()
 
 	log.Info("client [InitGame]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return fmt.Errorf("checkStatus: %w", err)
 	}
@@ -90,8 +116,8 @@ func (c *Client) InitGame(payload models.GamePayload) error {
 	return nil
 }
 
-func (c *Client) GetStatus() (models.StatusData, error) {
-	req, err := c.newRequestWithToken(http.MethodGet, "/game", nil)
+func (c *Client) GetStatus(ctx context.Context) (models.StatusData, error) {
+	req, err := c.newRequestWithToken(ctx, http.MethodGet, "/game", nil)
 	if err != nil {
 		return models.StatusData{}, fmt.Errorf("client.newRequestWithToken: %w", err)
 	}
@@ -103,7 +129,7 @@ func (c *Client) GetStatus() (models.StatusData, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetStatus]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.StatusData{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -118,8 +144,8 @@ func (c *Client) GetStatus() (models.StatusData, error) {
 	return data, nil
 }
 
-func (c *Client) GetBoard() (models.Board, error) {
-	req, err := c.newRequestWithToken(http.MethodGet, "/game/board", nil)
+func (c *Client) GetBoard(ctx context.Context) (models.Board, error) {
+	req, err := c.newRequestWithToken(ctx, http.MethodGet, "/game/board", nil)
 	if err != nil {
 		return models.Board{}, fmt.Errorf("client.newRequestWithToken: %w", err)
 	}
@@ -131,7 +157,7 @@ func (c *Client) GetBoard() (models.Board, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetBoard]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.Board{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -146,8 +172,8 @@ func (c *Client) GetBoard() (models.Board, error) {
 	return data, nil
 }
 
-func (c *Client) GetDescription() (models.StatusData, error) {
-	req, err := c.newRequestWithToken(http.MethodGet, "/game/desc", nil)
+func (c *Client) GetDescription(ctx context.Context) (models.StatusData, error) {
+	req, err := c.newRequestWithToken(ctx, http.MethodGet, "/game/desc", nil)
 	if err != nil {
 		return models.StatusData{}, fmt.Errorf("client.newRequestWithToken: %w", err)
 	}
@@ -159,7 +185,7 @@ func (c *Client) GetDescription() (models.StatusData, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetDescription]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.StatusData{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -173,7 +199,7 @@ func (c *Client) GetDescription() (models.StatusData, error) {
 	return data, nil
 }
 
-func (c *Client) Fire(coord string) (models.FireAnswer, error) {
+func (c *Client) Fire(ctx context.Context, coord string) (models.FireAnswer, error) {
 	payload := models.FirePayload{Coord: coord}
 	payloadJson, err := json.Marshal(payload)
 	if err != nil {
@@ -182,7 +208,7 @@ func (c *Client) Fire(coord string) (models.FireAnswer, error) {
 
 	payloadReader := bytes.NewReader(payloadJson)
 
-	req, err := c.newRequestWithToken(http.MethodPost, "/game/fire", payloadReader)
+	req, err := c.newRequestWithToken(ctx, http.MethodPost, "/game/fire", payloadReader)
 	if err != nil {
 		return models.FireAnswer{}, fmt.Errorf("client.newRequestWithToken: %w", err)
 	}
@@ -195,7 +221,7 @@ func (c *Client) Fire(coord string) (models.FireAnswer, error) {
 	defer res.Body.Close()
 
 	log.Info("client [Fire]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.FireAnswer{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -210,8 +236,8 @@ func (c *Client) Fire(coord string) (models.FireAnswer, error) {
 	return data, nil
 }
 
-func (c *Client) GetPlayersList() ([]models.ListData, error) {
-	req, err := c.newRequest(http.MethodGet, "/game/list", nil)
+func (c *Client) GetPlayersList(ctx context.Context) ([]models.ListData, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/game/list", nil)
 	if err != nil {
 		return []models.ListData{}, fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -223,7 +249,7 @@ func (c *Client) GetPlayersList() ([]models.ListData, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetPlayersList]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return []models.ListData{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -238,8 +264,8 @@ func (c *Client) GetPlayersList() ([]models.ListData, error) {
 	return data, nil
 }
 
-func (c *Client) RefreshSession() error {
-	req, err := c.newRequestWithToken(http.MethodGet, "/game/refresh", nil)
+func (c *Client) RefreshSession(ctx context.Context) error {
+	req, err := c.newRequestWithToken(ctx, http.MethodGet, "/game/refresh", nil)
 	if err != nil {
 		return fmt.Errorf("client.newRequestWithToken: %w", err)
 	}
@@ -251,15 +277,15 @@ func (c *Client) RefreshSession() error {
 	defer res.Body.Close()
 
 	log.Info("client [RefreshSession]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return fmt.Errorf("checkStatus: %w", err)
 	}
 	return nil
 }
 
-func (c *Client) GetStats() (models.StatsList, error) {
-	req, err := c.newRequest(http.MethodGet, "/stats", nil)
+func (c *Client) GetStats(ctx context.Context) (models.StatsList, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/stats", nil)
 	if err != nil {
 		return models.StatsList{}, fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -271,7 +297,7 @@ func (c *Client) GetStats() (models.StatsList, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetStats]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.StatsList{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -286,8 +312,8 @@ func (c *Client) GetStats() (models.StatsList, error) {
 	return data, nil
 }
 
-func (c *Client) GetPlayerStats(nick string) (models.StatsNick, error) {
-	req, err := c.newRequest(http.MethodGet, "/stats/"+nick, nil)
+func (c *Client) GetPlayerStats(ctx context.Context, nick string) (models.StatsNick, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/stats/"+nick, nil)
 	if err != nil {
 		return models.StatsNick{}, fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -299,7 +325,7 @@ func (c *Client) GetPlayerStats(nick string) (models.StatsNick, error) {
 	defer res.Body.Close()
 
 	log.Info("client [GetPlayerStats]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return models.StatsNick{}, fmt.Errorf("checkStatus: %w", err)
 	}
@@ -314,8 +340,8 @@ func (c *Client) GetPlayerStats(nick string) (models.StatsNick, error) {
 	return data, nil
 }
 
-func (c *Client) AbandonGame() error {
-	req, err := c.newRequestWithToken(http.MethodDelete, "/game/abandon", nil)
+func (c *Client) AbandonGame(ctx context.Context) error {
+	req, err := c.newRequestWithToken(ctx, http.MethodDelete, "/game/abandon", nil)
 	if err != nil {
 		return fmt.Errorf("client.newRequest: %w", err)
 	}
@@ -327,7 +353,7 @@ func (c *Client) AbandonGame() error {
 	defer res.Body.Close()
 
 	log.Info("client [AbandonGame]", "statusCode", res.StatusCode)
-	err = checkStatus(res.StatusCode)
+	err = checkStatus(res)
 	if err != nil {
 		return fmt.Errorf("checkStatus: %w", err)
 	}
@@ -349,19 +375,96 @@ func getFromResponseBody[T any](res *http.Response, target *T) error {
 	return nil
 }
 
-func checkStatus(status int) error {
-	switch status {
-	case 400:
-		return ErrBadRequest
-	case 401:
-		return ErrUnauthorized
-	case 403:
-		return ErrForbidden
-	case 404:
-		return ErrNotFound
-	case 503:
-		return ErrServiceUnavailable
-	default:
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Message    string
+	RequestID  string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id: %s)", e.RequestID)
+	}
+	return msg
+}
+
+func (e *APIError) Is(target error) bool {
+	return sentinels[e.StatusCode] == target
+}
+
+func (e *APIError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+func (e *APIError) RetryDelay() time.Duration {
+	return e.RetryAfter
+}
+
+func (e *APIError) Reason() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return http.StatusText(e.StatusCode)
+}
+
+func checkStatus(res *http.Response) error {
+	if res.StatusCode < 400 {
 		return nil
 	}
+
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get("X-Request-Id"),
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+	}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.Path = res.Request.URL.Path
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil {
+		log.Warn("client [checkStatus]", "err", fmt.Errorf("io.ReadAll: %w", err))
+	}
+	apiErr.Message = parseErrorMessage(body)
+	return apiErr
+}
+
+func parseErrorMessage(body []byte) string {
+	var data struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &data) == nil {
+		if data.Message != "" {
+			return data.Message
+		}
+		if data.Error != "" {
+			return data.Error
+		}
+	}
+	if len(body) > 0 && body[0] == '{' {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return 0
 }
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		header        map[string]string
+		body          string
+		wantMessage   string
+		wantReason    string
+		wantRequestID string
+		wantRetry     time.Duration
+		wantSentinel  error
+		wantTransient bool
+	}{
+		{
+			name:         "message field",
+			status:       http.StatusBadRequest,
+			body:         `{"message":"already fired at A1"}`,
+			wantMessage:  "already fired at A1",
+			wantReason:   "already fired at A1",
+			wantSentinel: ErrBadRequest,
+		},
+		{
+			name:         "error field",
+			status:       http.StatusForbidden,
+			body:         `{"error":"not your turn"}`,
+			wantMessage:  "not your turn",
+			wantReason:   "not your turn",
+			wantSentinel: ErrForbidden,
+		},
+		{
+			name:          "plain text with a request id",
+			status:        http.StatusUnauthorized,
+			header:        map[string]string{"X-Request-Id": "req-42"},
+			body:          "  session expired \n",
+			wantMessage:   "session expired",
+			wantReason:    "session expired",
+			wantRequestID: "req-42",
+			wantSentinel:  ErrUnauthorized,
+		},
+		{
+			name:         "json without a message",
+			status:       http.StatusNotFound,
+			body:         `{"code":404}`,
+			wantReason:   "Not Found",
+			wantSentinel: ErrNotFound,
+		},
+		{
+			name:          "empty body",
+			status:        http.StatusServiceUnavailable,
+			wantReason:    "Service Unavailable",
+			wantSentinel:  ErrServiceUnavailable,
+			wantTransient: true,
+		},
+		{
+			name:          "too many requests",
+			status:        http.StatusTooManyRequests,
+			header:        map[string]string{"Retry-After": "3"},
+			wantReason:    "Too Many Requests",
+			wantRetry:     3 * time.Second,
+			wantSentinel:  ErrTooManyRequests,
+			wantTransient: true,
+		},
+		{
+			name:          "status without a sentinel",
+			status:        http.StatusBadGateway,
+			body:          "<html>bad gateway</html>",
+			wantMessage:   "<html>bad gateway</html>",
+			wantReason:    "<html>bad gateway</html>",
+			wantTransient: true,
+		},
+		{
+			name:       "teapot",
+			status:     http.StatusTeapot,
+			wantReason: "I'm a teapot",
+		},
+	}
+	sentinels := []error{ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrTooManyRequests, ErrInternalServer, ErrServiceUnavailable}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := NewClient(srv.URL, time.Second).Fire(context.Background(), "A1")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Fire err = %v, want an *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Method != http.MethodPost || apiErr.Path != "/game/fire" {
+				t.Errorf("error for %d %s %s, want %d POST /game/fire", apiErr.StatusCode, apiErr.Method, apiErr.Path, tt.status)
+			}
+			if apiErr.Message != tt.wantMessage || apiErr.Reason() != tt.wantReason {
+				t.Errorf("message %q, reason %q, want %q, %q", apiErr.Message, apiErr.Reason(), tt.wantMessage, tt.wantReason)
+			}
+			if apiErr.RequestID != tt.wantRequestID {
+				t.Errorf("request id %q, want %q", apiErr.RequestID, tt.wantRequestID)
+			}
+			if tt.wantRequestID != "" && !strings.Contains(err.Error(), tt.wantRequestID) {
+				t.Errorf("error %q does not mention the request id", err)
+			}
+			if apiErr.RetryDelay() != tt.wantRetry {
+				t.Errorf("RetryDelay() = %v, want %v", apiErr.RetryDelay(), tt.wantRetry)
+			}
+			if apiErr.Transient() != tt.wantTransient {
+				t.Errorf("Transient() = %v, want %v", apiErr.Transient(), tt.wantTransient)
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.wantSentinel) {
+					t.Errorf("errors.Is(err, %v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header   string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"0", 0, 0},
+		{"3", 3 * time.Second, 3 * time.Second},
+		{"soon", 0, 0},
+		{time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat), time.Minute, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.header, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestSuccessIsNotAnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"result":"hit"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, time.Second)
+	c.SetToken("secret")
+	answer, err := c.Fire(context.Background(), "A1")
+	if err != nil || answer.Result != "hit" {
+		t.Errorf("Fire = %+v, %v, want a hit", answer, err)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/server"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	turnTime := flag.Duration("turn", 60*time.Second, "time a player has to fire")
+	debug := flag.Bool("debug", false, "enable debug logs")
+	flag.Parse()
+
+	log.SetOutput(os.Stderr)
+	if *debug {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", server.New(*turnTime)))
+
+	log.Info("server [main] - listening", "addr", *addr, "api", "http://localhost"+*addr+"/api")
+	err := http.ListenAndServe(*addr, mux)
+	if err != nil {
+		log.Fatal("server [main]", "err", err)
+	}
+}
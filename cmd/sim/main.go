@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/wojtekolesinski/battleships/sim"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"os"
+	"strings"
+)
+
+func main() {
+	names := strings.Join(strategy.Names(), ", ")
+	a := flag.String("a", strategy.Default, fmt.Sprintf("first strategy (%s)", names))
+	b := flag.String("b", "", "second strategy, plays head to head against the first one when set")
+	games := flag.Int("games", 1000, "number of games to simulate")
+	seed := flag.Int64("seed", 1, "random seed, the same seed always gives the same results")
+	flag.Parse()
+
+	if *games <= 0 {
+		fmt.Println("games must be positive")
+		os.Exit(1)
+	}
+
+	for _, name := range []string{*a, *b} {
+		if name == "" {
+			continue
+		}
+		res, err := sim.Solo(name, *games, *seed)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Strategy %s vs random fleets (%d games, seed %d)\n", name, *games, *seed)
+		printSummary(sim.Summarize(res.Shots))
+	}
+
+	if *b == "" {
+		return
+	}
+
+	res, err := sim.HeadToHead(*a, *b, *games, *seed)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Head to head: %s vs %s (%d games, seed %d)\n", *a, *b, *games, *seed)
+	fmt.Printf("  %s wins %d (%.1f%%), %s wins %d (%.1f%%)\n",
+		*a, res.WinsA, 100*float64(res.WinsA)/float64(*games),
+		*b, res.WinsB, 100*float64(res.WinsB)/float64(*games),
+	)
+	fmt.Println("  shots needed by the winner:")
+	printSummary(sim.Summarize(res.Shots))
+}
+
+func printSummary(s sim.Summary) {
+	fmt.Printf("  mean %.2f ± %.2f shots, min %d, max %d\n", s.Mean, s.StdDev, s.Min, s.Max)
+	var parts []string
+	for _, p := range sim.ReportedPercentiles {
+		parts = append(parts, fmt.Sprintf("p%d %d", p, s.Percentiles[p]))
+	}
+	fmt.Printf("  %s\n", strings.Join(parts, "  "))
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/retry"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config holds the settings of the client. They are layered: the defaults are
+// overridden by the config file, then by the environment and finally by the
+// command line flags.
+type Config struct {
+	Server      string   `json:"server"`
+	HTTPTimeout Duration `json:"http_timeout"`
+	Retry       Retry    `json:"retry"`
+	Nick        string   `json:"nick"`
+	Desc        string   `json:"desc"`
+	Board       string   `json:"board"`
+	Strategy    string   `json:"strategy"`
+	LogLevel    string   `json:"log_level"`
+	LogPath     string   `json:"log_path"`
+	ReplayDir   string   `json:"replay_dir"`
+	Theme       Theme    `json:"theme"`
+}
+
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelay   Duration `json:"base_delay"`
+	MaxDelay    Duration `json:"max_delay"`
+	Budget      Duration `json:"budget"`
+	Jitter      float64  `json:"jitter"`
+}
+
+func Default() Config {
+	p := retry.DefaultPolicy()
+	return Config{
+		Server:      "https://go-pjatk-server.fly.dev/api",
+		HTTPTimeout: Duration(10 * time.Second),
+		Retry: Retry{
+			MaxAttempts: p.MaxAttempts,
+			BaseDelay:   Duration(p.BaseDelay),
+			MaxDelay:    Duration(p.MaxDelay),
+			Budget:      Duration(p.Budget),
+			Jitter:      p.Jitter,
+		},
+		LogLevel: "debug",
+		Theme:    DefaultTheme(),
+	}
+}
+
+// Path returns the location of the config file, $BATTLESHIPS_CONFIG or
+// config.json in the user's config directory.
+func Path() (string, error) {
+	if path, ok := os.LookupEnv(envPrefix + "CONFIG"); ok {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, "battleships", "config.json"), nil
+}
+
+// Load reads the config file, if there is one, on top of the defaults and
+// applies the environment overrides.
+func Load() (Config, error) {
+	cfg := Default()
+	path, err := Path()
+	if err != nil {
+		log.Warn("config [Load] - config file disabled", "err", err)
+	} else {
+		err = cfg.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	err = cfg.ApplyEnv(os.LookupEnv)
+	if err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// ReadFile overrides the settings present in the file at path. A missing file
+// is not an error.
+func (c *Config) ReadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	var problems []string
+	if c.Server == "" {
+		problems = append(problems, "server must not be empty")
+	}
+	if c.HTTPTimeout <= 0 {
+		problems = append(problems, "http_timeout must be positive")
+	}
+	if c.Retry.MaxAttempts < 1 {
+		problems = append(problems, "retry.max_attempts must be at least 1")
+	}
+	if c.Retry.BaseDelay < 0 || c.Retry.MaxDelay < 0 || c.Retry.Budget < 0 {
+		problems = append(problems, "retry delays must not be negative")
+	}
+	if c.Retry.Jitter < 0 || c.Retry.Jitter > 1 {
+		problems = append(problems, "retry.jitter must be between 0 and 1")
+	}
+	if log.ParseLevel(c.LogLevel).String() != strings.ToLower(c.LogLevel) {
+		problems = append(problems, fmt.Sprintf("unknown log level %q", c.LogLevel))
+	}
+	for name, color := range c.Theme.colors() {
+		if _, _, _, err := color.RGB(); err != nil {
+			problems = append(problems, fmt.Sprintf("theme.%s: %s", name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (r Retry) Policy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts: r.MaxAttempts,
+		BaseDelay:   time.Duration(r.BaseDelay),
+		MaxDelay:    time.Duration(r.MaxDelay),
+		Budget:      time.Duration(r.Budget),
+		Jitter:      r.Jitter,
+	}
+}
+
+// Duration is a time.Duration written as a string like "10s" in the file.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("duration must be a string like \"10s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(data), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	cfg := Default()
+	path := writeConfig(t, `{"server": "http://localhost:8080/api", "retry": {"max_attempts": 7, "base_delay": "1s"}, "theme": {"hit": "#ff0000"}}`)
+	err := cfg.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	want := Default()
+	want.Server = "http://localhost:8080/api"
+	want.Retry.MaxAttempts = 7
+	want.Retry.BaseDelay = Duration(time.Second)
+	want.Theme.Hit = "#ff0000"
+	if cfg != want {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	cfg := Default()
+	err := cfg.ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || cfg != Default() {
+		t.Errorf("a missing file gave %v and changed the config to %+v", err, cfg)
+	}
+
+	for _, data := range []string{`{"server": `, `{"http_timeout": 10}`, `{"http_timeout": "soon"}`} {
+		path := writeConfig(t, data)
+		if err := cfg.ReadFile(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("ReadFile(%s) = %v, want an error naming the file", data, err)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg := Default()
+	err := cfg.ApplyEnv(env(map[string]string{
+		"BATTLESHIPS_SERVER":         "http://env/api",
+		"BATTLESHIPS_HTTP_TIMEOUT":   "3s",
+		"BATTLESHIPS_RETRY_ATTEMPTS": "2",
+		"BATTLESHIPS_RETRY_JITTER":   "0.5",
+		"BATTLESHIPS_NICK":           "",
+		"BATTLESHIPS_THEME_PICK":     "#000000",
+		"SERVER":                     "http://unprefixed/api",
+	}))
+	if err != nil {
+		t.Fatalf("ApplyEnv: %v", err)
+	}
+
+	want := Default()
+	want.Server = "http://env/api"
+	want.HTTPTimeout = Duration(3 * time.Second)
+	want.Retry.MaxAttempts = 2
+	want.Retry.Jitter = 0.5
+	want.Theme.Pick = "#000000"
+	if cfg != want {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	tests := map[string]string{
+		"BATTLESHIPS_HTTP_TIMEOUT":   "10",
+		"BATTLESHIPS_RETRY_ATTEMPTS": "many",
+		"BATTLESHIPS_RETRY_JITTER":   "half",
+	}
+	for name, value := range tests {
+		cfg := Default()
+		err := cfg.ApplyEnv(env(map[string]string{name: value}))
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s=%s gave %v, want an error naming the variable", name, value, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"upper case log level", func(c *Config) { c.LogLevel = "WARN" }, ""},
+		{"empty server", func(c *Config) { c.Server = "" }, "server must not be empty"},
+		{"zero timeout", func(c *Config) { c.HTTPTimeout = 0 }, "http_timeout must be positive"},
+		{"no attempts", func(c *Config) { c.Retry.MaxAttempts = 0 }, "retry.max_attempts"},
+		{"negative delay", func(c *Config) { c.Retry.Budget = Duration(-time.Second) }, "must not be negative"},
+		{"jitter above one", func(c *Config) { c.Retry.Jitter = 1.5 }, "retry.jitter"},
+		{"unknown log level", func(c *Config) { c.LogLevel = "verbose" }, `unknown log level "verbose"`},
+		{"bad colour", func(c *Config) { c.Theme.Ship = "green" }, "theme.ship"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(&cfg)
+			err := cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := Default()
+	cfg.Server, cfg.Retry.Jitter = "", -1
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "server") || !strings.Contains(err.Error(), "jitter") {
+		t.Errorf("Validate = %v, want both problems", err)
+	}
+}
+
+// TestOverrideOrder layers the settings the way main does: the file over the
+// defaults, the environment over the file and the flags over both.
+func TestOverrideOrder(t *testing.T) {
+	path := writeConfig(t, `{"server": "http://file/api", "nick": "file", "desc": "from the file", "log_level": "info"}`)
+	cfg := Default()
+	err := cfg.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	err = cfg.ApplyEnv(env(map[string]string{
+		"BATTLESHIPS_NICK":      "env",
+		"BATTLESHIPS_LOG_LEVEL": "warn",
+	}))
+	if err != nil {
+		t.Fatalf("ApplyEnv: %v", err)
+	}
+
+	flags := flag.NewFlagSet("battleships", flag.ContinueOnError)
+	nick := flags.String("nick", cfg.Nick, "")
+	desc := flags.String("desc", cfg.Desc, "")
+	server := flags.String("server", cfg.Server, "")
+	logLevel := flags.String("log-level", cfg.LogLevel, "")
+	err = flags.Parse([]string{"-log-level", "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Nick, cfg.Desc, cfg.Server, cfg.LogLevel = *nick, *desc, *server, *logLevel
+
+	want := map[string][2]string{
+		"server":    {cfg.Server, "http://file/api"},
+		"desc":      {cfg.Desc, "from the file"},
+		"nick":      {cfg.Nick, "env"},
+		"log level": {cfg.LogLevel, "error"},
+		"strategy":  {cfg.Strategy, Default().Strategy},
+	}
+	for name, v := range want {
+		if v[0] != v[1] {
+			t.Errorf("%s = %q, want %q", name, v[0], v[1])
+		}
+	}
+}
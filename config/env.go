@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const envPrefix = "BATTLESHIPS_"
+
+// ApplyEnv overrides the settings for which lookup finds a BATTLESHIPS_
+// variable, e.g. BATTLESHIPS_SERVER or BATTLESHIPS_RETRY_ATTEMPTS.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	vars := []struct {
+		name string
+		set  func(string) error
+	}{
+		{"SERVER", setString(&c.Server)},
+		{"HTTP_TIMEOUT", setDuration(&c.HTTPTimeout)},
+		{"RETRY_ATTEMPTS", setInt(&c.Retry.MaxAttempts)},
+		{"RETRY_BASE_DELAY", setDuration(&c.Retry.BaseDelay)},
+		{"RETRY_MAX_DELAY", setDuration(&c.Retry.MaxDelay)},
+		{"RETRY_BUDGET", setDuration(&c.Retry.Budget)},
+		{"RETRY_JITTER", setFloat(&c.Retry.Jitter)},
+		{"NICK", setString(&c.Nick)},
+		{"DESC", setString(&c.Desc)},
+		{"BOARD", setString(&c.Board)},
+		{"STRATEGY", setString(&c.Strategy)},
+		{"LOG_LEVEL", setString(&c.LogLevel)},
+		{"LOG_PATH", setString(&c.LogPath)},
+		{"REPLAY_DIR", setString(&c.ReplayDir)},
+		{"THEME_EMPTY", setColor(&c.Theme.Empty)},
+		{"THEME_HIT", setColor(&c.Theme.Hit)},
+		{"THEME_MISS", setColor(&c.Theme.Miss)},
+		{"THEME_SHIP", setColor(&c.Theme.Ship)},
+		{"THEME_PICK", setColor(&c.Theme.Pick)},
+	}
+
+	for _, v := range vars {
+		value, ok := lookup(envPrefix + v.name)
+		if !ok {
+			continue
+		}
+		err := v.set(value)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", envPrefix, v.name, err)
+		}
+	}
+	return nil
+}
+
+func setString(dst *string) func(string) error {
+	return func(s string) error {
+		*dst = s
+		return nil
+	}
+}
+
+func setColor(dst *Color) func(string) error {
+	return func(s string) error {
+		*dst = Color(s)
+		return nil
+	}
+}
+
+func setInt(dst *int) func(string) error {
+	return func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		*dst = v
+		return nil
+	}
+}
+
+func setFloat(dst *float64) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*dst = v
+		return nil
+	}
+}
+
+func setDuration(dst *Duration) func(string) error {
+	return func(s string) error {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*dst = Duration(v)
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the colours of the board tiles in the full-screen interface.
+type Theme struct {
+	Empty Color `json:"empty"`
+	Hit   Color `json:"hit"`
+	Miss  Color `json:"miss"`
+	Ship  Color `json:"ship"`
+	Pick  Color `json:"pick"`
+}
+
+// Color is written as "#rrggbb".
+type Color string
+
+func DefaultTheme() Theme {
+	return Theme{
+		Empty: "#63a1b8",
+		Hit:   "#e61e16",
+		Miss:  "#696969",
+		Ship:  "#5bb516",
+		Pick:  "#7e8e00",
+	}
+}
+
+func (t Theme) colors() map[string]Color {
+	return map[string]Color{
+		"empty": t.Empty,
+		"hit":   t.Hit,
+		"miss":  t.Miss,
+		"ship":  t.Ship,
+		"pick":  t.Pick,
+	}
+}
+
+func (c Color) RGB() (r, g, b uint8, err error) {
+	s := strings.TrimPrefix(string(c), "#")
+	if len(s) != 6 {
+		return 0, 0, 0, fmt.Errorf("colour %q is not in the #rrggbb format", c)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("colour %q is not in the #rrggbb format", c)
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
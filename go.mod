@@ -4,6 +4,8 @@ go 1.19
 
 require (
 	github.com/charmbracelet/log v0.2.1
+	github.com/google/uuid v1.3.0
+	github.com/grupawp/termloop v0.0.0-20230531144437-277a1cbf4c14
 	github.com/grupawp/warships-gui/v2 v2.1.5
 	github.com/mitchellh/go-wordwrap v1.0.1
 )
@@ -12,8 +14,6 @@ require (
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
 	github.com/charmbracelet/lipgloss v0.7.1 // indirect
 	github.com/go-logfmt/logfmt v0.6.0 // indirect
-	github.com/google/uuid v1.3.0 // indirect
-	github.com/grupawp/termloop v0.0.0-20230531144437-277a1cbf4c14 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.18 // indirect
 	github.com/mattn/go-runewidth v0.0.14 // indirect
@@ -1,18 +1,49 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/charmbracelet/log"
 	"github.com/wojtekolesinski/battleships/app"
 	"github.com/wojtekolesinski/battleships/client"
+	"github.com/wojtekolesinski/battleships/config"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"io"
 	"os"
+	"strings"
 	"time"
 )
 
-const (
-	serverAddress     = "https://go-pjatk-server.fly.dev/api"
-	httpClientTimeout = 10 * time.Second
-)
+const usage = `Usage:
+  %[1]s [flags] [log file]        play interactively
+  %[1]s play [flags]              play a game against wp_bot
+  %[1]s challenge [flags] NICK    play a game against NICK
+  %[1]s wait [flags]              wait for an invitation and play it
+  %[1]s stats [flags] [NICK]      show the stats of NICK, -nick by default
+  %[1]s top [flags]               show the top 10 players
+  %[1]s replay [flags] FILE       watch a recorded game
+  %[1]s analyze [flags] FILE [OUT]
+                                  compare your shots in a recorded game with the
+                                  best ones, OUT can be a .md, .html or .txt file
+
+Games started with play, challenge and wait are played by the bot without asking
+any questions, so they can run from scripts. Flags may be given before or after
+the command.
+
+Flags:
+`
+
+var commands = map[string]bool{
+	"play":      true,
+	"challenge": true,
+	"wait":      true,
+	"stats":     true,
+	"top":       true,
+	"replay":    true,
+	"analyze":   true,
+}
 
 func main() {
 	//var board app.Board
@@ -31,26 +62,163 @@ func main() {
 	//}
 	//os.Exit(0)
 
-	logPath := fmt.Sprintf("%s.log", time.Now().Format("02-01-2006"))
-	if len(os.Args) > 1 {
-		logPath = os.Args[1]
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if cfg.LogPath == "" {
+		cfg.LogPath = fmt.Sprintf("%s.log", time.Now().Format("02-01-2006"))
+	}
+
+	strategyName := flag.String("strategy", cfg.Strategy, fmt.Sprintf("strategy used by the bot and the assistant (%s)", strings.Join(strategy.Names(), ", ")))
+	boardPath := flag.String("board", cfg.Board, "load your board from a file (grid, JSON or coordinate list)")
+	textMode := flag.Bool("text", false, "play in plain text instead of the full-screen interface")
+	nick := flag.String("nick", cfg.Nick, "your nick, asked for when playing interactively without it")
+	desc := flag.String("desc", cfg.Desc, "your description")
+	server := flag.String("server", cfg.Server, "address of the game server API")
+	httpTimeout := flag.Duration("http-timeout", time.Duration(cfg.HTTPTimeout), "timeout of a single request to the server")
+	logPath := flag.String("log", cfg.LogPath, "file the logs are written to")
+	logLevel := flag.String("log-level", cfg.LogLevel, "debug, info, warn, error or fatal")
+	timeout := flag.Duration("timeout", 0, "give up after this long, 0 means never")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, os.Args[0])
+		flag.PrintDefaults()
 	}
-	w, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0777)
+	flag.Parse()
+
+	command, args := "", flag.Args()
+	if len(args) > 0 && commands[args[0]] {
+		command = args[0]
+		flag.CommandLine.Parse(args[1:])
+		args = flag.Args()
+	}
+	isSet := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { isSet[f.Name] = true })
+
+	if command == "" && len(args) > 0 && !isSet["log"] {
+		*logPath = args[0]
+	}
+	cfg.Server, cfg.HTTPTimeout, cfg.LogLevel = *server, config.Duration(*httpTimeout), *logLevel
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w, err := os.OpenFile(*logPath, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0777)
 	if err != nil {
 		panic(err)
 	}
 	defer w.Close()
 
 	log.SetOutput(w)
-	log.SetLevel(log.DebugLevel)
-	c := client.NewClient(serverAddress, httpClientTimeout)
+	log.SetLevel(log.ParseLevel(cfg.LogLevel))
+	err = app.SetTheme(cfg.Theme)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	app.SetRetryPolicy(cfg.Retry.Policy())
+
+	c := client.NewClient(cfg.Server, time.Duration(cfg.HTTPTimeout))
 	a := app.New(c)
+	a.SetTextMode(*textMode)
+	if cfg.ReplayDir != "" {
+		a.SetReplayDir(cfg.ReplayDir)
+	}
+	if *nick != "" || *desc != "" || isSet["nick"] || isSet["desc"] {
+		a.SetPlayer(*nick, *desc)
+	}
+	if *strategyName != "" {
+		err = a.SetStrategy(*strategyName)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
 
-	err = a.Run()
-	if err != nil {
+	if *boardPath != "" {
+		err = a.LoadBoard(*boardPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	if command != "" {
+		err = runCommand(ctx, a, command, args, *nick)
+		if err != nil {
+			log.Error("main [main]", "command", command, "err", err)
+			fmt.Println(err)
+			w.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	err = a.Run(ctx)
+	if errors.Is(err, io.EOF) {
+		log.Info("main [main] - input closed", "err", err)
+		fmt.Println("\nInput closed")
+	} else if err != nil {
 		log.Error("main [main]", "err", err)
 		fmt.Println("Something went wrong")
 	}
 	log.Info("main [main] - ENDING GAME")
 	fmt.Println("Thanks for playing")
 }
+
+func runCommand(ctx context.Context, a *app.App, command string, args []string, nick string) error {
+	var opponent string
+	switch command {
+	case "play":
+		opponent = "wp_bot"
+	case "challenge":
+		if len(args) != 1 {
+			return fmt.Errorf("challenge needs the nick of the opponent")
+		}
+		opponent = args[0]
+	case "wait":
+		fmt.Println("Waiting for an invitation...")
+	case "stats":
+		if len(args) > 0 {
+			nick = args[0]
+		}
+		if nick == "" {
+			return fmt.Errorf("stats needs a nick, pass it as an argument or with -nick")
+		}
+		return a.DisplayPlayerStats(ctx, nick)
+	case "top":
+		return a.DisplayTop10Stats(ctx)
+	case "replay":
+		if len(args) != 1 {
+			return fmt.Errorf("replay needs the path of the replay file")
+		}
+		return a.ViewReplay(ctx, args[0])
+	case "analyze":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("analyze needs the path of the replay file and optionally of the report")
+		}
+		out := ""
+		if len(args) == 2 {
+			out = args[1]
+		}
+		return a.AnalyzeReplay(args[0], out)
+	}
+
+	result, err := a.Play(ctx, opponent)
+	if err != nil {
+		return err
+	}
+	log.Info("main [runCommand] - game finished", "result", result)
+	fmt.Printf("Result: %s\n", result)
+	return nil
+}
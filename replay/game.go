@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/wojtekolesinski/battleships/board"
+	"io"
+	"os"
+)
+
+// Game is a parsed replay.
+type Game struct {
+	Start Event
+	// shots of both players in the order they were fired
+	Moves []Event
+	// nil when the recording was cut short
+	End *Event
+}
+
+// Frame is the state of the game after a number of moves.
+type Frame struct {
+	Step int
+	// our ships as Occupied cells, hit with the opponent's shots
+	Player board.Board
+	// our shots
+	Opponent board.Board
+	// opponent's ships that are still afloat
+	Fleet board.Fleet
+	Shots int
+	Hits  int
+	// the move that led to the frame, nil before the first move
+	Last *Event
+}
+
+func Load(path string) (*Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+func Read(r io.Reader) (*Game, error) {
+	g := &Game{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		err := json.Unmarshal(s.Bytes(), &e)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if line == 1 {
+			if e.Type != TypeStart {
+				return nil, fmt.Errorf("line 1: expected a %s event, got %q", TypeStart, e.Type)
+			}
+			if e.Version > Version {
+				return nil, fmt.Errorf("replay version %d is newer than the supported %d", e.Version, Version)
+			}
+			g.Start = e
+			continue
+		}
+
+		switch e.Type {
+		case TypeShot, TypeOppShot:
+			_, err = board.ParsePoint(e.Coord)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			g.Moves = append(g.Moves, e)
+		case TypeEnd:
+			g.End = &e
+		default:
+			return nil, fmt.Errorf("line %d: unknown event %q", line, e.Type)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("bufio.Scanner: %w", err)
+	}
+	if g.Start.Type == "" {
+		return nil, fmt.Errorf("the replay is empty")
+	}
+	return g, nil
+}
+
+// Frame replays the first step moves.
+func (g *Game) Frame(step int) Frame {
+	if step > len(g.Moves) {
+		step = len(g.Moves)
+	}
+
+	f := Frame{Step: step, Fleet: board.StandardFleet()}
+	for _, c := range g.Start.Board {
+		p, err := board.ParsePoint(c)
+		if err == nil {
+			f.Player.Set(p, board.Occupied)
+		}
+	}
+
+	for i := 0; i < step; i++ {
+		e := &g.Moves[i]
+		p, _ := board.ParsePoint(e.Coord)
+		f.Last = e
+		if e.Type == TypeOppShot {
+			if e.Result == "miss" {
+				f.Player.Set(p, board.Water)
+			} else {
+				f.Player.Set(p, board.Hit)
+			}
+			continue
+		}
+
+		f.Shots++
+		if e.Result != "miss" {
+			f.Hits++
+		}
+		if ship := f.Opponent.Record(p, e.Result); ship != nil {
+			f.Fleet[len(ship)]--
+		}
+	}
+	return f
+}
+
+// Turns returns the number of moves played at the end of every turn, a turn
+// being all the shots a player fires in a row.
+func (g *Game) Turns() []int {
+	var ends []int
+	for i := range g.Moves {
+		if i == len(g.Moves)-1 || g.Moves[i+1].Type != g.Moves[i].Type {
+			ends = append(ends, i+1)
+		}
+	}
+	return ends
+}
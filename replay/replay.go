@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Version is written in the start event of every replay and bumped whenever
+// the meaning of the events changes.
+const Version = 1
+
+const (
+	TypeStart   = "start"
+	TypeShot    = "shot"
+	TypeOppShot = "opp_shot"
+	TypeEnd     = "end"
+)
+
+// Event is a single line of a replay file. Only the fields relevant to the
+// type are set.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// start
+	Version  int      `json:"version,omitempty"`
+	Nick     string   `json:"nick,omitempty"`
+	Desc     string   `json:"desc,omitempty"`
+	Opponent string   `json:"opponent,omitempty"`
+	OppDesc  string   `json:"opp_desc,omitempty"`
+	Board    []string `json:"board,omitempty"`
+	Strategy string   `json:"strategy,omitempty"`
+	Bot      bool     `json:"bot,omitempty"`
+
+	// shot, opp_shot
+	Coord  string `json:"coord,omitempty"`
+	Result string `json:"result,omitempty"`
+	// seconds left in the turn when the shot was fired, our shots only
+	Timer int `json:"timer,omitempty"`
+
+	// end
+	Status string `json:"status,omitempty"`
+}
+
+// Recorder appends events to a replay file, one JSON object per line.
+type Recorder struct {
+	path string
+	f    *os.File
+	enc  *json.Encoder
+}
+
+// FileName returns a name for the replay of a game against opponent that
+// started at t, such that the replays sort by time.
+func FileName(t time.Time, opponent string) string {
+	opponent = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, opponent)
+	return fmt.Sprintf("%s-%s.jsonl", t.Format("2006-01-02-150405"), opponent)
+}
+
+// Open opens the replay at path for appending, creating it and its directory
+// when needed.
+func Open(path string) (*Recorder, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+	return &Recorder{path: path, f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+func (r *Recorder) Record(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Type == TypeStart {
+		e.Version = Version
+	}
+	err := r.enc.Encode(e)
+	if err != nil {
+		return fmt.Errorf("json.Encoder.Encode: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) Close() error {
+	return r.f.Close()
+}
@@ -0,0 +1,205 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	randMu sync.Mutex
+	random = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Budget      time.Duration
+	Jitter      float64
+}
+
+type Attempt struct {
+	Err      error
+	Duration time.Duration
+	Delay    time.Duration
+}
+
+type Error struct {
+	Attempts []Attempt
+	Reason   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s after %d attempt(s): %s", e.Reason, len(e.Attempts), e.Last())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Last()
+}
+
+func (e *Error) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    4 * time.Second,
+		Budget:      15 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+func (p Policy) Do(ctx context.Context, target func(ctx context.Context) error) error {
+	var attempts []Attempt
+	start := time.Now()
+
+	for i := 0; ; i++ {
+		attemptStart := time.Now()
+		err := target(ctx)
+		if err == nil {
+			return nil
+		}
+		attempt := Attempt{Err: err, Duration: time.Since(attemptStart)}
+
+		var reason string
+		switch {
+		case ctx.Err() != nil:
+			reason = "cancelled"
+		case !IsTransient(err):
+			reason = "permanent error"
+		case i+1 >= p.MaxAttempts:
+			reason = "retries exhausted"
+		}
+		if reason != "" {
+			return &Error{Attempts: append(attempts, attempt), Reason: reason}
+		}
+
+		attempt.Delay = p.delay(i, err)
+		if p.Budget > 0 && time.Since(start)+attempt.Delay > p.Budget {
+			return &Error{Attempts: append(attempts, attempt), Reason: "retry budget exceeded"}
+		}
+		attempts = append(attempts, attempt)
+		log.Warn("retry [Do]", "attempt", i+1, "delay", attempt.Delay, "err", err)
+
+		t := time.NewTimer(attempt.Delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return &Error{Attempts: attempts, Reason: "cancelled"}
+		case <-t.C:
+		}
+	}
+}
+
+func (p Policy) delay(attempt int, err error) time.Duration {
+	d := p.BaseDelay << attempt
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		randMu.Lock()
+		d -= time.Duration(p.Jitter * random.Float64() * float64(d))
+		randMu.Unlock()
+	}
+
+	if after, ok := RetryAfter(err); ok && after > d {
+		d = after
+	}
+	return d
+}
+
+// IsTransient reports whether a request that failed with err is worth
+// retrying: timeouts, dropped or refused connections, temporary DNS failures
+// and errors that say so themselves. Other transport errors, like a bad TLS
+// certificate or an unsupported scheme, will fail the same way again.
+func IsTransient(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var transient interface{ Transient() bool }
+	if errors.As(err, &transient) {
+		return transient.Transient()
+	}
+
+	if timeout(err) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || dropped(err)
+}
+
+// MaybeDelivered reports whether a request that failed with err might still
+// have been handled by the server: it timed out, the connection dropped or
+// the server answered with an error worth retrying, like a 502 from a proxy
+// after the request went through. Requests that are not idempotent must not
+// be retried then.
+func MaybeDelivered(err error) bool {
+	if err == nil {
+		return false
+	}
+	var p permanent
+	if errors.As(err, &p) {
+		err = p.error
+	}
+	var transient interface{ Transient() bool }
+	if errors.As(err, &transient) && transient.Transient() {
+		return true
+	}
+	return timeout(err) || dropped(err)
+}
+
+func timeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// dropped reports a connection closed before the whole response came.
+func dropped(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+type permanent struct {
+	error
+}
+
+func (p permanent) Unwrap() error {
+	return p.error
+}
+
+func (permanent) Transient() bool {
+	return false
+}
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanent{err}
+}
+
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryAfter interface{ RetryDelay() time.Duration }
+	if errors.As(err, &retryAfter) && retryAfter.RetryDelay() > 0 {
+		return retryAfter.RetryDelay(), true
+	}
+	return 0, false
+}
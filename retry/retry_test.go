@@ -0,0 +1,193 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// statusErr is an error answered by the server, like client.APIError.
+type statusErr struct {
+	code  int
+	after time.Duration
+}
+
+func (e statusErr) Error() string {
+	return fmt.Sprintf("status %d", e.code)
+}
+
+func (e statusErr) Transient() bool {
+	return e.code == 429 || e.code >= 500
+}
+
+func (e statusErr) RetryDelay() time.Duration {
+	return e.after
+}
+
+// failing returns a target that fails with errs one after another and
+// succeeds once they run out.
+func failing(errs ...error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls > len(errs) {
+			return nil
+		}
+		return errs[calls-1]
+	}, &calls
+}
+
+func TestDo(t *testing.T) {
+	fast := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Jitter: 0.5}
+	tests := []struct {
+		name       string
+		policy     Policy
+		errs       []error
+		wantCalls  int
+		wantReason string
+	}{
+		{name: "success", policy: fast, wantCalls: 1},
+		{name: "transient then success", policy: fast, errs: []error{io.EOF, statusErr{code: 503}}, wantCalls: 3},
+		{name: "permanent status", policy: fast, errs: []error{statusErr{code: 400}}, wantCalls: 1, wantReason: "permanent error"},
+		{name: "marked permanent", policy: fast, errs: []error{Permanent(io.EOF)}, wantCalls: 1, wantReason: "permanent error"},
+		{name: "retries exhausted", policy: fast, errs: []error{io.EOF, io.EOF, io.EOF, io.EOF}, wantCalls: 3, wantReason: "retries exhausted"},
+		{
+			name:       "budget exceeded",
+			policy:     Policy{MaxAttempts: 5, BaseDelay: time.Second, Budget: 100 * time.Millisecond},
+			errs:       []error{io.EOF},
+			wantCalls:  1,
+			wantReason: "retry budget exceeded",
+		},
+		{
+			name:       "retry after beyond the budget",
+			policy:     Policy{MaxAttempts: 5, BaseDelay: time.Millisecond, Budget: 100 * time.Millisecond},
+			errs:       []error{statusErr{code: 429, after: time.Minute}},
+			wantCalls:  1,
+			wantReason: "retry budget exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, calls := failing(tt.errs...)
+			err := tt.policy.Do(context.Background(), target)
+			if *calls != tt.wantCalls {
+				t.Errorf("%d calls, want %d", *calls, tt.wantCalls)
+			}
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("Do: %v", err)
+				}
+				return
+			}
+			var retryErr *Error
+			if !errors.As(err, &retryErr) || retryErr.Reason != tt.wantReason {
+				t.Fatalf("Do err = %v, want the reason %q", err, tt.wantReason)
+			}
+			if len(retryErr.Attempts) != tt.wantCalls {
+				t.Errorf("%d attempts recorded, want %d", len(retryErr.Attempts), tt.wantCalls)
+			}
+			if !errors.Is(err, tt.errs[tt.wantCalls-1]) {
+				t.Errorf("Do err = %v does not wrap the last error", err)
+			}
+		})
+	}
+}
+
+func TestDoCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := Policy{MaxAttempts: 5, BaseDelay: time.Minute}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := p.Do(ctx, func(context.Context) error { return io.EOF })
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || retryErr.Reason != "cancelled" {
+		t.Errorf("Do err = %v, want cancelled", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Do waited %v after the context was cancelled", time.Since(start))
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Jitter: 0.5}
+	tests := []struct {
+		attempt  int
+		err      error
+		min, max time.Duration
+	}{
+		{attempt: 0, err: io.EOF, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 1, err: io.EOF, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 5, err: io.EOF, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+		{attempt: 100, err: io.EOF, min: 150 * time.Millisecond, max: 300 * time.Millisecond},
+		{attempt: 0, err: statusErr{code: 429, after: 2 * time.Second}, min: 2 * time.Second, max: 2 * time.Second},
+		{attempt: 1, err: statusErr{code: 503, after: time.Millisecond}, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if d := p.delay(tt.attempt, tt.err); d < tt.min || d > tt.max {
+				t.Fatalf("delay(%d, %v) = %v, want between %v and %v", tt.attempt, tt.err, d, tt.min, tt.max)
+			}
+		}
+	}
+
+	p.Jitter = 0
+	if d := p.delay(1, io.EOF); d != 200*time.Millisecond {
+		t.Errorf("delay without jitter = %v, want 200ms", d)
+	}
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestClassification(t *testing.T) {
+	dial := func(err error) error {
+		return &url.Error{Op: "Post", URL: "http://localhost/game/fire", Err: &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: err}}}
+	}
+	tests := []struct {
+		name          string
+		err           error
+		wantTransient bool
+		wantDelivered bool
+	}{
+		{"nil", nil, false, false},
+		{"cancelled", fmt.Errorf("wrapped: %w", context.Canceled), false, false},
+		{"deadline", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), true, true},
+		{"network timeout", &url.Error{Op: "Get", URL: "http://localhost", Err: timeoutErr{}}, true, true},
+		{"connection refused", dial(syscall.ECONNREFUSED), true, false},
+		{"connection reset", dial(syscall.ECONNRESET), true, true},
+		{"eof", &url.Error{Op: "Post", URL: "http://localhost", Err: io.EOF}, true, true},
+		{"temporary dns", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true, false},
+		{"unknown host", &net.DNSError{Err: "no such host", IsNotFound: true}, false, false},
+		{"unsupported scheme", &url.Error{Op: "Get", URL: "ftp://localhost", Err: errors.New(`unsupported protocol scheme "ftp"`)}, false, false},
+		{"bad request", statusErr{code: 400}, false, false},
+		{"too many requests", statusErr{code: 429}, true, true},
+		{"bad gateway", fmt.Errorf("wrapped: %w", statusErr{code: 502}), true, true},
+		{"permanent timeout", Permanent(context.DeadlineExceeded), false, true},
+		{"permanent bad gateway", &Error{Attempts: []Attempt{{Err: Permanent(statusErr{code: 502})}}}, false, true},
+		{"permanent refused", Permanent(dial(syscall.ECONNREFUSED)), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err != nil && IsTransient(tt.err); got != tt.wantTransient {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.wantTransient)
+			}
+			if got := MaybeDelivered(tt.err); got != tt.wantDelivered {
+				t.Errorf("MaybeDelivered(%v) = %v, want %v", tt.err, got, tt.wantDelivered)
+			}
+		})
+	}
+}
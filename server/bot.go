@@ -0,0 +1,44 @@
+package server
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+)
+
+const botNick = "wp_bot"
+
+type botShooter struct {
+	board   board.Board
+	pending []board.Point
+}
+
+func newBotShooter() *botShooter {
+	return &botShooter{}
+}
+
+func (b *botShooter) next(r *rand.Rand) board.Point {
+	for len(b.pending) > 0 {
+		var p board.Point
+		p, b.pending = b.pending[0], b.pending[1:]
+		if b.board.At(p) == board.Unknown {
+			return p
+		}
+	}
+
+	candidates := b.board.Find(board.Unknown)
+	return candidates[r.Intn(len(candidates))]
+}
+
+func (b *botShooter) record(p board.Point, result string) {
+	b.board.Record(p, result)
+	switch result {
+	case "hit":
+		for _, n := range p.Neighbours() {
+			if b.board.At(n) == board.Unknown {
+				b.pending = append(b.pending, n)
+			}
+		}
+	case "sunk":
+		b.pending = nil
+	}
+}
@@ -0,0 +1,477 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/models"
+	mrand "math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	lobbyTimeout = 60 * time.Second
+	// ended games are kept for the players to read the result
+	endedTimeout = 5 * time.Minute
+	botDelay     = time.Second
+	pointsPerWin = 10
+)
+
+type session struct {
+	token      string
+	nick       string
+	desc       string
+	targetNick string
+	fleet      *board.Layout
+	shots      []string
+	game       *game
+	result     string
+	lastSeen   time.Time
+	bot        *botShooter
+}
+
+type game struct {
+	players     [2]*session
+	turn        int
+	turnStarted time.Time
+	ended       bool
+	endedAt     time.Time
+}
+
+type Server struct {
+	mu       sync.Mutex
+	mux      *http.ServeMux
+	rand     *mrand.Rand
+	turnTime time.Duration
+	sessions map[string]*session
+	stats    map[string]*models.StatsData
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func New(turnTime time.Duration) *Server {
+	s := &Server{
+		mux:      http.NewServeMux(),
+		rand:     mrand.New(mrand.NewSource(time.Now().UnixNano())),
+		turnTime: turnTime,
+		sessions: make(map[string]*session),
+		stats:    make(map[string]*models.StatsData),
+	}
+
+	s.mux.HandleFunc("/game", s.handleGame)
+	s.mux.HandleFunc("/game/board", s.withSession(http.MethodGet, s.handleBoard))
+	s.mux.HandleFunc("/game/desc", s.withSession(http.MethodGet, s.handleDescription))
+	s.mux.HandleFunc("/game/fire", s.withSession(http.MethodPost, s.handleFire))
+	s.mux.HandleFunc("/game/refresh", s.withSession(http.MethodGet, s.handleRefresh))
+	s.mux.HandleFunc("/game/abandon", s.withSession(http.MethodDelete, s.handleAbandon))
+	s.mux.HandleFunc("/game/list", s.handleList)
+	s.mux.HandleFunc("/stats", s.handleStats)
+	s.mux.HandleFunc("/stats/", s.handlePlayerStats)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log.Debug("server [ServeHTTP]", "method", r.Method, "path", r.URL.Path)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tick(time.Now())
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) withSession(method string, handler func(http.ResponseWriter, *http.Request, *session)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		sess, ok := s.sessions[r.Header.Get("X-Auth-Token")]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing or invalid auth token")
+			return
+		}
+		sess.lastSeen = time.Now()
+		handler(w, r, sess)
+	}
+}
+
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.handleInitGame(w, r)
+	case http.MethodGet:
+		s.withSession(http.MethodGet, s.handleStatus)(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleInitGame(w http.ResponseWriter, r *http.Request) {
+	var payload models.GamePayload
+	err := json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %s", err))
+		return
+	}
+
+	if payload.Nick == "" {
+		payload.Nick = fmt.Sprintf("player_%04d", s.rand.Intn(10000))
+	}
+	if payload.Desc == "" {
+		payload.Desc = "Local warships player"
+	}
+	if payload.Nick == botNick || s.nickInUse(payload.Nick) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("nick %s is already in use", payload.Nick))
+		return
+	}
+
+	var f *board.Layout
+	if len(payload.Coords) > 0 {
+		f, err = board.ParseLayout(payload.Coords)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid board: %s", err))
+			return
+		}
+	} else {
+		f = board.RandomLayout(s.rand)
+	}
+
+	var opponent *session
+	if !payload.Wpbot && payload.TargetNick != "" {
+		opponent = s.waitingSession(payload.TargetNick, payload.Nick)
+		if opponent == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("player %s is not waiting for a game", payload.TargetNick))
+			return
+		}
+	}
+
+	s.dropSessions(payload.Nick)
+	sess := &session{
+		token:      newToken(),
+		nick:       payload.Nick,
+		desc:       payload.Desc,
+		targetNick: payload.TargetNick,
+		fleet:      f,
+		lastSeen:   time.Now(),
+	}
+	s.sessions[sess.token] = sess
+
+	if payload.Wpbot {
+		opponent = &session{
+			nick:  botNick,
+			desc:  "Warships bot",
+			fleet: board.RandomLayout(s.rand),
+			bot:   newBotShooter(),
+		}
+	}
+	if opponent != nil {
+		s.startGame(sess, opponent)
+	}
+
+	log.Info("server [initGame]", "nick", sess.nick, "wpbot", payload.Wpbot, "target", payload.TargetNick)
+	w.Header().Set("X-Auth-Token", sess.token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request, sess *session) {
+	status := s.description(sess)
+	status.LastGameStatus = sess.result
+
+	switch {
+	case sess.game == nil:
+		status.GameStatus = "waiting"
+	case sess.game.ended:
+		status.GameStatus = "ended"
+		status.OppShots = sess.game.opponent(sess).shots
+	default:
+		g := sess.game
+		status.GameStatus = "game_in_progress"
+		status.ShouldFire = g.players[g.turn] == sess
+		status.OppShots = g.opponent(sess).shots
+		remaining := s.turnTime - time.Since(g.turnStarted)
+		status.Timer = int((remaining + time.Second - 1) / time.Second)
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleBoard(w http.ResponseWriter, _ *http.Request, sess *session) {
+	writeJSON(w, http.StatusOK, models.Board{Board: sess.fleet.Coords()})
+}
+
+func (s *Server) handleDescription(w http.ResponseWriter, _ *http.Request, sess *session) {
+	writeJSON(w, http.StatusOK, s.description(sess))
+}
+
+func (s *Server) handleFire(w http.ResponseWriter, r *http.Request, sess *session) {
+	var payload models.FirePayload
+	err := json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid payload: %s", err))
+		return
+	}
+
+	g := sess.game
+	if g == nil || g.ended {
+		writeError(w, http.StatusBadRequest, "game is not in progress")
+		return
+	}
+	if g.players[g.turn] != sess {
+		writeError(w, http.StatusBadRequest, "it is not your turn")
+		return
+	}
+
+	p, err := board.ParsePoint(payload.Coord)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, shot := range sess.shots {
+		if shot == p.String() {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("already fired at %s", p))
+			return
+		}
+	}
+
+	result := s.fire(g, p)
+	writeJSON(w, http.StatusOK, models.FireAnswer{Result: result})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, _ *http.Request, _ *session) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleAbandon(w http.ResponseWriter, _ *http.Request, sess *session) {
+	if sess.game == nil {
+		delete(s.sessions, sess.token)
+	} else if !sess.game.ended {
+		s.endGame(sess.game, sess.game.opponent(sess))
+	}
+	log.Info("server [abandon]", "nick", sess.nick)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	list := []models.ListData{}
+	for _, sess := range s.sessions {
+		if sess.game == nil {
+			list = append(list, models.ListData{GameStatus: "waiting", Nick: sess.nick})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Nick < list[j].Nick })
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	stats := s.rankedStats()
+	if len(stats) > 10 {
+		stats = stats[:10]
+	}
+	writeJSON(w, http.StatusOK, models.StatsList{Stats: stats})
+}
+
+func (s *Server) handlePlayerStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	nick := strings.TrimPrefix(r.URL.Path, "/stats/")
+	for _, st := range s.rankedStats() {
+		if st.Nick == nick {
+			writeJSON(w, http.StatusOK, models.StatsNick{Stats: st})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no stats for player %s", nick))
+}
+
+func (s *Server) description(sess *session) models.StatusData {
+	status := models.StatusData{
+		Nick: sess.nick,
+		Desc: sess.desc,
+	}
+	if sess.game != nil {
+		opp := sess.game.opponent(sess)
+		status.Opponent = opp.nick
+		status.OppDesc = opp.desc
+	}
+	return status
+}
+
+func (s *Server) startGame(a, b *session) {
+	g := &game{
+		players:     [2]*session{a, b},
+		turn:        s.rand.Intn(2),
+		turnStarted: time.Now(),
+	}
+	for _, p := range g.players {
+		p.game = g
+		p.shots = []string{}
+		p.result = ""
+	}
+	log.Info("server [startGame]", "player1", a.nick, "player2", b.nick, "first", g.players[g.turn].nick)
+}
+
+func (s *Server) fire(g *game, p board.Point) string {
+	shooter := g.players[g.turn]
+	target := g.opponent(shooter)
+	result, _ := target.fleet.Fire(p)
+	shooter.shots = append(shooter.shots, p.String())
+	g.turnStarted = time.Now()
+	log.Debug("server [fire]", "nick", shooter.nick, "coord", p.String(), "result", result)
+
+	if target.fleet.Destroyed() {
+		s.endGame(g, shooter)
+	} else if result == "miss" {
+		g.turn = 1 - g.turn
+	}
+	return result
+}
+
+func (s *Server) endGame(g *game, winner *session) {
+	g.ended = true
+	g.endedAt = time.Now()
+	for _, p := range g.players {
+		st, ok := s.stats[p.nick]
+		if !ok {
+			st = &models.StatsData{Nick: p.nick}
+			s.stats[p.nick] = st
+		}
+		st.Games++
+
+		if p == winner {
+			p.result = "win"
+			st.Wins++
+			st.Points += pointsPerWin
+		} else {
+			p.result = "lose"
+		}
+	}
+	log.Info("server [endGame]", "winner", winner.nick)
+}
+
+func (s *Server) tick(now time.Time) {
+	for token, sess := range s.sessions {
+		g := sess.game
+		if g == nil {
+			if now.Sub(sess.lastSeen) > lobbyTimeout {
+				log.Info("server [tick] - lobby session expired", "nick", sess.nick)
+				delete(s.sessions, token)
+			}
+			continue
+		}
+
+		if g.ended {
+			if now.Sub(g.endedAt) > endedTimeout {
+				log.Info("server [tick] - ended game expired", "nick", sess.nick)
+				delete(s.sessions, token)
+			}
+			continue
+		}
+		if now.Sub(g.turnStarted) > s.turnTime {
+			log.Info("server [tick] - turn timed out", "nick", g.players[g.turn].nick)
+			s.endGame(g, g.opponent(g.players[g.turn]))
+			continue
+		}
+
+		for !g.ended && g.players[g.turn].bot != nil && now.Sub(g.turnStarted) >= botDelay {
+			b := g.players[g.turn].bot
+			p := b.next(s.rand)
+			b.record(p, s.fire(g, p))
+		}
+	}
+}
+
+func (s *Server) nickInUse(nick string) bool {
+	for _, sess := range s.sessions {
+		if sess.nick == nick && (sess.game == nil || !sess.game.ended) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) waitingSession(nick, challenger string) *session {
+	for _, sess := range s.sessions {
+		if sess.nick == nick && sess.game == nil && (sess.targetNick == "" || sess.targetNick == challenger) {
+			return sess
+		}
+	}
+	return nil
+}
+
+func (s *Server) dropSessions(nick string) {
+	for token, sess := range s.sessions {
+		if sess.nick == nick {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+func (s *Server) rankedStats() []models.StatsData {
+	var stats []models.StatsData
+	for _, st := range s.stats {
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Points != stats[j].Points {
+			return stats[i].Points > stats[j].Points
+		}
+		if stats[i].Wins != stats[j].Wins {
+			return stats[i].Wins > stats[j].Wins
+		}
+		return stats[i].Nick < stats[j].Nick
+	})
+	for i := range stats {
+		stats[i].Rank = i + 1
+	}
+	return stats
+}
+
+func (g *game) opponent(sess *session) *session {
+	if g.players[0] == sess {
+		return g.players[1]
+	}
+	return g.players[0]
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error("server [writeJSON]", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Message: message})
+}
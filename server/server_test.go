@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/wojtekolesinski/battleships/client"
+	"github.com/wojtekolesinski/battleships/models"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, turnTime time.Duration) (*Server, string) {
+	t.Helper()
+	s := New(turnTime)
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv.URL
+}
+
+func join(t *testing.T, url string, payload models.GamePayload) *client.Client {
+	t.Helper()
+	c := client.NewClient(url, 5*time.Second)
+	err := c.InitGame(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("InitGame(%+v): %v", payload, err)
+	}
+	return c
+}
+
+func status(t *testing.T, c *client.Client) models.StatusData {
+	t.Helper()
+	st, err := c.GetStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	return st
+}
+
+// startMatch lets alice wait for bob and returns the players in the order
+// they fire.
+func startMatch(t *testing.T, url string) (shooter, target *client.Client) {
+	t.Helper()
+	alice := join(t, url, models.GamePayload{Nick: "alice"})
+	if st := status(t, alice); st.GameStatus != "waiting" {
+		t.Fatalf("alice's game status = %q, want waiting", st.GameStatus)
+	}
+	bob := join(t, url, models.GamePayload{Nick: "bob", TargetNick: "alice"})
+
+	a, b := status(t, alice), status(t, bob)
+	if a.GameStatus != "game_in_progress" || b.GameStatus != "game_in_progress" {
+		t.Fatalf("game statuses = %q, %q, want game_in_progress", a.GameStatus, b.GameStatus)
+	}
+	if a.Opponent != "bob" || b.Opponent != "alice" {
+		t.Fatalf("opponents = %q, %q", a.Opponent, b.Opponent)
+	}
+	if a.ShouldFire == b.ShouldFire {
+		t.Fatalf("should fire = %v, %v, want exactly one player", a.ShouldFire, b.ShouldFire)
+	}
+	if a.ShouldFire {
+		return alice, bob
+	}
+	return bob, alice
+}
+
+func TestGameUntilTheFleetIsSunk(t *testing.T) {
+	_, url := newTestServer(t, time.Minute)
+	ctx := context.Background()
+	shooter, target := startMatch(t, url)
+
+	fleet, err := target.GetBoard(ctx)
+	if err != nil {
+		t.Fatalf("GetBoard: %v", err)
+	}
+	if len(fleet.Board) != 20 {
+		t.Fatalf("the board has %d cells, want 20", len(fleet.Board))
+	}
+	for i, coord := range fleet.Board {
+		answer, err := shooter.Fire(ctx, coord)
+		if err != nil {
+			t.Fatalf("Fire(%s): %v", coord, err)
+		}
+		if answer.Result == "miss" {
+			t.Fatalf("Fire(%s) = miss on a ship cell", coord)
+		}
+		if i < len(fleet.Board)-1 && status(t, shooter).GameStatus != "game_in_progress" {
+			t.Fatalf("the game ended after %d shots", i+1)
+		}
+	}
+
+	winner, loser := status(t, shooter), status(t, target)
+	if winner.GameStatus != "ended" || winner.LastGameStatus != "win" {
+		t.Errorf("winner status = %q/%q, want ended/win", winner.GameStatus, winner.LastGameStatus)
+	}
+	if loser.GameStatus != "ended" || loser.LastGameStatus != "lose" {
+		t.Errorf("loser status = %q/%q, want ended/lose", loser.GameStatus, loser.LastGameStatus)
+	}
+	if !reflect.DeepEqual(loser.OppShots, fleet.Board) {
+		t.Errorf("loser's opp shots = %v, want all the winning shots %v", loser.OppShots, fleet.Board)
+	}
+
+	stats, err := shooter.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	want := models.StatsData{Nick: winner.Nick, Games: 1, Wins: 1, Rank: 1, Points: pointsPerWin}
+	if len(stats.Stats) != 2 || stats.Stats[0] != want {
+		t.Errorf("stats = %+v, want %+v first of 2", stats.Stats, want)
+	}
+	player, err := shooter.GetPlayerStats(ctx, loser.Nick)
+	if err != nil {
+		t.Fatalf("GetPlayerStats: %v", err)
+	}
+	if player.Stats.Games != 1 || player.Stats.Wins != 0 || player.Stats.Rank != 2 {
+		t.Errorf("loser's stats = %+v", player.Stats)
+	}
+}
+
+func TestRejectedRequests(t *testing.T) {
+	_, url := newTestServer(t, time.Minute)
+	ctx := context.Background()
+	shooter, target := startMatch(t, url)
+	fleet, err := target.GetBoard(ctx)
+	if err != nil {
+		t.Fatalf("GetBoard: %v", err)
+	}
+	_, err = shooter.Fire(ctx, fleet.Board[0])
+	if err != nil {
+		t.Fatalf("Fire(%s): %v", fleet.Board[0], err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"not your turn", func() error { _, err := target.Fire(ctx, "A1"); return err }, client.ErrBadRequest},
+		{"invalid coordinate", func() error { _, err := shooter.Fire(ctx, "K1"); return err }, client.ErrBadRequest},
+		{"repeated shot", func() error { _, err := shooter.Fire(ctx, fleet.Board[0]); return err }, client.ErrBadRequest},
+		{"repeated shot with a leading zero", func() error {
+			c := fleet.Board[0]
+			_, err := shooter.Fire(ctx, c[:1]+"0"+c[1:])
+			return err
+		}, client.ErrBadRequest},
+		{"no token", func() error { _, err := client.NewClient(url, time.Second).GetStatus(ctx); return err }, client.ErrUnauthorized},
+		{"taken nick", func() error {
+			return client.NewClient(url, time.Second).InitGame(ctx, models.GamePayload{Nick: "alice"})
+		}, client.ErrBadRequest},
+		{"nobody waiting", func() error {
+			return client.NewClient(url, time.Second).InitGame(ctx, models.GamePayload{Nick: "carol", TargetNick: "dave"})
+		}, client.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTurnTimeout(t *testing.T) {
+	_, url := newTestServer(t, 50*time.Millisecond)
+	shooter, target := startMatch(t, url)
+	time.Sleep(100 * time.Millisecond)
+
+	if st := status(t, shooter); st.GameStatus != "ended" || st.LastGameStatus != "lose" {
+		t.Errorf("status of the player who did not fire = %q/%q, want ended/lose", st.GameStatus, st.LastGameStatus)
+	}
+	if st := status(t, target); st.LastGameStatus != "win" {
+		t.Errorf("status of the opponent = %q, want win", st.LastGameStatus)
+	}
+}
+
+func TestLobbyAndBot(t *testing.T) {
+	_, url := newTestServer(t, time.Minute)
+	ctx := context.Background()
+	join(t, url, models.GamePayload{Nick: "alice"})
+	player := join(t, url, models.GamePayload{Nick: "bob", Wpbot: true})
+
+	list, err := player.GetPlayersList(ctx)
+	if err != nil {
+		t.Fatalf("GetPlayersList: %v", err)
+	}
+	want := []models.ListData{{GameStatus: "waiting", Nick: "alice"}}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("players list = %+v, want %+v", list, want)
+	}
+
+	st := status(t, player)
+	if st.GameStatus != "game_in_progress" || st.Opponent != botNick {
+		t.Errorf("status = %q against %q, want game_in_progress against %s", st.GameStatus, st.Opponent, botNick)
+	}
+}
+
+func TestEndedSessionsExpire(t *testing.T) {
+	s, url := newTestServer(t, time.Minute)
+	ctx := context.Background()
+	shooter, target := startMatch(t, url)
+
+	err := shooter.AbandonGame(ctx)
+	if err != nil {
+		t.Fatalf("AbandonGame: %v", err)
+	}
+	if st := status(t, target); st.GameStatus != "ended" || st.LastGameStatus != "win" {
+		t.Fatalf("status after the opponent left = %q/%q, want ended/win", st.GameStatus, st.LastGameStatus)
+	}
+
+	s.mu.Lock()
+	s.tick(time.Now().Add(endedTimeout - time.Second))
+	kept := len(s.sessions)
+	s.tick(time.Now().Add(endedTimeout + time.Second))
+	left := len(s.sessions)
+	s.mu.Unlock()
+	if kept != 2 || left != 0 {
+		t.Errorf("sessions = %d within the grace period and %d after it, want 2 and 0", kept, left)
+	}
+	if _, err := target.GetStatus(ctx); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("status of an expired session: err = %v, want %v", err, client.ErrUnauthorized)
+	}
+}
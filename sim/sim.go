@@ -0,0 +1,172 @@
+package sim
+
+import (
+	"fmt"
+	"github.com/wojtekolesinski/battleships/board"
+	"github.com/wojtekolesinski/battleships/strategy"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+const maxShots = 200
+
+type Result struct {
+	Shots []int
+	WinsA int
+	WinsB int
+}
+
+type player struct {
+	strategy strategy.Strategy
+	state    strategy.State
+	target   *board.Layout
+}
+
+func newPlayer(name string, target *board.Layout, r *rand.Rand) (*player, error) {
+	s, err := strategy.New(name, r)
+	if err != nil {
+		return nil, fmt.Errorf("strategy.New: %w", err)
+	}
+
+	p := &player{
+		strategy: s,
+		target:   target,
+		state:    strategy.State{Fleet: board.StandardFleet()},
+	}
+	return p, nil
+}
+
+func (p *player) shoot() (string, error) {
+	if len(p.state.Shots) >= maxShots {
+		return "", fmt.Errorf("no win after %d shots", maxShots)
+	}
+
+	target := p.strategy.Recommend(p.state).Point
+	if !target.InBounds() || p.state.Board.At(target) != board.Unknown {
+		return "", fmt.Errorf("strategy fired at %v which is not an unknown cell", target)
+	}
+
+	result, _ := p.target.Fire(target)
+	p.state.Shots = append(p.state.Shots, strategy.Shot{Point: target, Result: result})
+	if ship := p.state.Board.Record(target, result); ship != nil {
+		p.state.Fleet[len(ship)]--
+	}
+	return result, nil
+}
+
+func playSolo(name string, r *rand.Rand) (int, error) {
+	p, err := newPlayer(name, board.RandomLayout(r), rand.New(rand.NewSource(r.Int63())))
+	if err != nil {
+		return 0, err
+	}
+
+	for !p.target.Destroyed() {
+		_, err = p.shoot()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p.state.Shots), nil
+}
+
+func playHeadToHead(a, b string, aStarts bool, r *rand.Rand) (bool, int, error) {
+	fleetA, fleetB := board.RandomLayout(r), board.RandomLayout(r)
+	pa, err := newPlayer(a, fleetB, rand.New(rand.NewSource(r.Int63())))
+	if err != nil {
+		return false, 0, err
+	}
+	pb, err := newPlayer(b, fleetA, rand.New(rand.NewSource(r.Int63())))
+	if err != nil {
+		return false, 0, err
+	}
+
+	current, other := pa, pb
+	if !aStarts {
+		current, other = pb, pa
+	}
+	for {
+		result, err := current.shoot()
+		if err != nil {
+			return false, 0, err
+		}
+		if current.target.Destroyed() {
+			return current == pa, len(current.state.Shots), nil
+		}
+		if result == "miss" {
+			current, other = other, current
+		}
+	}
+}
+
+func Solo(name string, games int, seed int64) (Result, error) {
+	var res Result
+	shots := make([]int, games)
+	err := run(games, seed, func(i int, r *rand.Rand) error {
+		n, err := playSolo(name, r)
+		shots[i] = n
+		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Shots = shots
+	return res, nil
+}
+
+func HeadToHead(a, b string, games int, seed int64) (Result, error) {
+	var res Result
+	shots := make([]int, games)
+	winsA := make([]bool, games)
+	err := run(games, seed, func(i int, r *rand.Rand) error {
+		won, n, err := playHeadToHead(a, b, i%2 == 0, r)
+		winsA[i] = won
+		shots[i] = n
+		return err
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.Shots = shots
+	for _, won := range winsA {
+		if won {
+			res.WinsA++
+		} else {
+			res.WinsB++
+		}
+	}
+	return res, nil
+}
+
+func run(games int, seed int64, play func(i int, r *rand.Rand) error) error {
+	master := rand.New(rand.NewSource(seed))
+	seeds := make([]int64, games)
+	for i := range seeds {
+		seeds[i] = master.Int63()
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, games)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := play(i, rand.New(rand.NewSource(seeds[i])))
+				if err != nil {
+					errs <- fmt.Errorf("game %d: %w", i, err)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < games; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
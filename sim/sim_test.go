@@ -0,0 +1,85 @@
+package sim
+
+import (
+	"github.com/wojtekolesinski/battleships/strategy"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSameSeedSameResult(t *testing.T) {
+	for _, name := range strategy.Names() {
+		t.Run(name, func(t *testing.T) {
+			first, err := Solo(name, 2, 7)
+			if err != nil {
+				t.Fatalf("Solo: %v", err)
+			}
+			second, err := Solo(name, 2, 7)
+			if err != nil {
+				t.Fatalf("Solo: %v", err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("seed 7 gave %v and then %v", first.Shots, second.Shots)
+			}
+			for i, n := range first.Shots {
+				if n < 20 || n > maxShots {
+					t.Errorf("game %d took %d shots", i, n)
+				}
+			}
+		})
+	}
+
+	first, err := HeadToHead("hunt-target", "monte-carlo", 2, 7)
+	if err != nil {
+		t.Fatalf("HeadToHead: %v", err)
+	}
+	second, err := HeadToHead("hunt-target", "monte-carlo", 2, 7)
+	if err != nil {
+		t.Fatalf("HeadToHead: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("seed 7 gave %+v and then %+v", first, second)
+	}
+	if first.WinsA+first.WinsB != 2 {
+		t.Errorf("wins = %d + %d, want 2 games", first.WinsA, first.WinsB)
+	}
+}
+
+func TestUnknownStrategy(t *testing.T) {
+	if _, err := Solo("psychic", 1, 1); err == nil {
+		t.Error("Solo accepted an unknown strategy")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name  string
+		shots []int
+		want  Summary
+	}{
+		{
+			name:  "no games",
+			shots: nil,
+			want:  Summary{Percentiles: map[int]int{}},
+		},
+		{
+			name:  "one game",
+			shots: []int{42},
+			want: Summary{Games: 1, Mean: 42, Min: 42, Max: 42,
+				Percentiles: map[int]int{10: 42, 25: 42, 50: 42, 75: 42, 90: 42, 99: 42}},
+		},
+		{
+			name:  "unsorted games",
+			shots: []int{50, 20, 40, 30},
+			want: Summary{Games: 4, Mean: 35, StdDev: math.Sqrt(125), Min: 20, Max: 50,
+				Percentiles: map[int]int{10: 20, 25: 20, 50: 30, 75: 40, 90: 50, 99: 50}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.shots); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summarize(%v) = %+v, want %+v", tt.shots, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package sim
+
+import (
+	"math"
+	"sort"
+)
+
+type Summary struct {
+	Games       int
+	Mean        float64
+	StdDev      float64
+	Min, Max    int
+	Percentiles map[int]int
+}
+
+var ReportedPercentiles = []int{10, 25, 50, 75, 90, 99}
+
+func Summarize(shots []int) Summary {
+	s := Summary{Games: len(shots), Percentiles: make(map[int]int)}
+	if len(shots) == 0 {
+		return s
+	}
+
+	sorted := append([]int(nil), shots...)
+	sort.Ints(sorted)
+	s.Min, s.Max = sorted[0], sorted[len(sorted)-1]
+
+	var sum float64
+	for _, n := range sorted {
+		sum += float64(n)
+	}
+	s.Mean = sum / float64(len(sorted))
+
+	var variance float64
+	for _, n := range sorted {
+		variance += (float64(n) - s.Mean) * (float64(n) - s.Mean)
+	}
+	s.StdDev = math.Sqrt(variance / float64(len(sorted)))
+
+	for _, p := range ReportedPercentiles {
+		idx := int(math.Ceil(float64(p)/100*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		s.Percentiles[p] = sorted[idx]
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"fmt"
+	"github.com/wojtekolesinski/battleships/board"
+	"sort"
+	"strings"
+)
+
+const (
+	ModeHunt   = "hunt"
+	ModeTarget = "target"
+	runnersUp  = 3
+)
+
+// What the scores of a recommendation count, strategies that do not score
+// the cells leave it empty.
+const (
+	ScorePlacements = "placements"
+	ScoreLayouts    = "layouts"
+)
+
+// Candidate is a cell the strategy could have chosen instead.
+type Candidate struct {
+	Point board.Point
+	Score int
+}
+
+// Recommendation is the cell chosen by a strategy with the data it chose it
+// by.
+type Recommendation struct {
+	Point board.Point
+	Mode  string
+	// how the strategy picks a cell in this mode
+	Rule string
+	// open hits next to the point
+	Hits []board.Point
+	// ScorePlacements, ScoreLayouts or empty
+	Scores string
+	Score  int
+	// the number of sampled layouts the scores come from
+	Layouts int
+	// ship length to its share of the score
+	Placements map[int]int
+	// the best scored cells after the point, or the next ones the strategy
+	// would have tried when it does not score them
+	RunnersUp []Candidate
+}
+
+func newRecommendation(state State, p board.Point, rule string) Recommendation {
+	rec := Recommendation{Point: p, Mode: ModeHunt, Rule: rule}
+	hits := state.Board.Find(board.Hit)
+	if len(hits) > 0 {
+		rec.Mode = ModeTarget
+	}
+	for _, h := range hits {
+		for _, n := range h.Neighbours() {
+			if n == p {
+				rec.Hits = append(rec.Hits, h)
+			}
+		}
+	}
+	return rec
+}
+
+// rank returns the best scored cells other than p.
+func rank(cells []board.Point, p board.Point, score func(board.Point) int) []Candidate {
+	var res []Candidate
+	for _, c := range cells {
+		if c != p {
+			res = append(res, Candidate{Point: c, Score: score(c)})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+	if len(res) > runnersUp {
+		res = res[:runnersUp]
+	}
+	return res
+}
+
+// inOrder returns the cells after the first one as unscored candidates.
+func inOrder(cells []board.Point) []Candidate {
+	var res []Candidate
+	for _, c := range cells[1:] {
+		if len(res) == runnersUp {
+			break
+		}
+		res = append(res, Candidate{Point: c})
+	}
+	return res
+}
+
+// Lines describes the recommendation for the player, a fact per line.
+func (r Recommendation) Lines() []string {
+	var lines []string
+	switch {
+	case r.Mode == ModeHunt:
+		lines = append(lines, "Hunting: no damaged ship to finish")
+	case len(r.Hits) > 0:
+		var hits []string
+		for _, h := range r.Hits {
+			hits = append(hits, h.String())
+		}
+		lines = append(lines, fmt.Sprintf("Targeting: next to the hit at %s", strings.Join(hits, ", ")))
+	default:
+		lines = append(lines, "Targeting: a damaged ship is afloat elsewhere")
+	}
+	lines = append(lines, fmt.Sprintf("Rule: %s", r.Rule))
+
+	switch {
+	case r.Scores == "":
+	case r.Score == 0:
+		lines = append(lines, fmt.Sprintf("No remaining ship fits on %s", r.Point))
+	case r.Scores == ScoreLayouts:
+		lines = append(lines, fmt.Sprintf("A ship is on %s in %d of %d sampled layouts:", r.Point, r.Score, r.Layouts))
+	default:
+		lines = append(lines, fmt.Sprintf("%d placements of the remaining ships cover %s:", r.Score, r.Point))
+	}
+	if r.Score > 0 {
+		for length := 4; length >= 1; length-- {
+			if n := r.Placements[length]; n > 0 {
+				lines = append(lines, fmt.Sprintf("  %d masted: %d", length, n))
+			}
+		}
+	}
+
+	if len(r.RunnersUp) > 0 {
+		var others []string
+		for _, c := range r.RunnersUp {
+			if r.Scores == "" {
+				others = append(others, c.Point.String())
+			} else {
+				others = append(others, fmt.Sprintf("%s (%d)", c.Point, c.Score))
+			}
+		}
+		label := "Runners-up"
+		if r.Scores == "" {
+			label = "Next in line"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", label, strings.Join(others, ", ")))
+	}
+	return lines
+}
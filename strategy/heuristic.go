@@ -0,0 +1,129 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+)
+
+type huntTarget struct{}
+
+func init() {
+	Register(Default, func(*rand.Rand) Strategy { return huntTarget{} })
+}
+
+func (huntTarget) Recommend(state State) Recommendation {
+	if cells := unknownTargets(state); len(cells) > 0 {
+		rec := newRecommendation(state, cells[0], "the first unknown cell next to a hit")
+		rec.RunnersUp = inOrder(cells)
+		return rec
+	}
+
+	counts := placements(state.Board, state.Fleet)
+	probs := sum(counts)
+	var max, x, y int
+
+	for i := range probs {
+		for j := range probs[i] {
+			if probs[i][j] > max {
+				max = probs[i][j]
+				x = i
+				y = j
+			}
+		}
+	}
+
+	p := board.Point{X: x, Y: y}
+	rec := newRecommendation(state, p, "the cell covered by most placements of the remaining ships")
+	rec.Scores = ScorePlacements
+	rec.Score = probs[x][y]
+	rec.Placements = share(counts, p)
+	rec.RunnersUp = rank(state.Board.Find(board.Unknown), p, func(c board.Point) int { return probs[c.X][c.Y] })
+	return rec
+}
+
+func targets(state State) []board.Point {
+	var targets []board.Point
+	for _, p := range state.Board.Find(board.Hit) {
+		targets = append(targets, p.Neighbours()...)
+	}
+	return targets
+}
+
+// unknownTargets returns the unknown cells next to the hits in the order they
+// are tried.
+func unknownTargets(state State) []board.Point {
+	var cells []board.Point
+	seen := map[board.Point]bool{}
+	for _, p := range targets(state) {
+		if state.Board.At(p) == board.Unknown && !seen[p] {
+			seen[p] = true
+			cells = append(cells, p)
+		}
+	}
+	return cells
+}
+
+func Probabilities(b board.Board, fleet board.Fleet) [board.Size][board.Size]int {
+	return sum(placements(b, fleet))
+}
+
+// placements counts for every ship length still afloat its placements on the
+// unknown cells that cover each cell.
+func placements(b board.Board, fleet board.Fleet) [5][board.Size][board.Size]int {
+	var counts [5][board.Size][board.Size]int
+
+	for length := 4; length >= 1; length-- {
+		if fleet[length] == 0 {
+			continue
+		}
+
+		for x := range b {
+			for y := range b[x] {
+				if b[x][y] != board.Unknown {
+					continue
+				}
+				for _, ship := range board.Shapes[length] {
+					if fits(ship, b, x, y) {
+						for _, p := range ship {
+							counts[length][x+p.X][y+p.Y]++
+						}
+					}
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func sum(counts [5][board.Size][board.Size]int) [board.Size][board.Size]int {
+	var total [board.Size][board.Size]int
+	for length := range counts {
+		for x := range counts[length] {
+			for y := range counts[length][x] {
+				total[x][y] += counts[length][x][y]
+			}
+		}
+	}
+	return total
+}
+
+// share returns the counts of every ship length on p.
+func share(counts [5][board.Size][board.Size]int, p board.Point) map[int]int {
+	res := map[int]int{}
+	for length := range counts {
+		if n := counts[length][p.X][p.Y]; n > 0 {
+			res[length] = n
+		}
+	}
+	return res
+}
+
+func fits(ship []board.Point, b board.Board, x int, y int) bool {
+	for _, p := range ship {
+		n := board.Point{X: p.X + x, Y: p.Y + y}
+		if !n.InBounds() || b.At(n) != board.Unknown {
+			return false
+		}
+	}
+	return true
+}
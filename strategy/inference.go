@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+)
+
+// Inference is what the shots so far tell about the ships still afloat.
+type Inference struct {
+	// ship length to the number of its legal placements
+	Placements map[int]int
+	// unknown cells that hold a ship in every consistent layout, not all of
+	// them are found
+	Certain []board.Point
+	// unknown cells no remaining ship can cover
+	Impossible []board.Point
+}
+
+// Infer works out the cells that must and that cannot hold a ship from the
+// legal placements of the remaining ships. Every open hit belongs to one of
+// the placements covering it and every remaining ship to one of the
+// placements of its length, so the cells common to all of them are certain.
+// The inference is sound but incomplete: the placements are not combined
+// into whole fleets, so a cell certain only because the ships cannot all fit
+// elsewhere at once is not marked.
+func Infer(state State) Inference {
+	inf := Inference{Placements: map[int]int{}}
+	sm := newSampler(state, nil)
+
+	var covered [board.Size][board.Size]bool
+	var certain [board.Size][board.Size]bool
+	for length := 1; length <= 4; length++ {
+		if sm.fleet[length] == 0 {
+			continue
+		}
+		inf.Placements[length] = len(sm.byLength[length])
+		for _, ship := range sm.byLength[length] {
+			for _, p := range ship.cells[:ship.length] {
+				covered[p.X][p.Y] = true
+			}
+		}
+		markCommon(&certain, sm.byLength[length])
+	}
+	for _, h := range sm.open {
+		markCommon(&certain, sm.covering[h.X][h.Y])
+	}
+
+	for _, p := range state.Board.Find(board.Unknown) {
+		switch {
+		case !covered[p.X][p.Y]:
+			inf.Impossible = append(inf.Impossible, p)
+		case certain[p.X][p.Y]:
+			inf.Certain = append(inf.Certain, p)
+		}
+	}
+	return inf
+}
+
+// markCommon marks the cells shared by all the placements, there are none
+// when there are no placements.
+func markCommon(marks *[board.Size][board.Size]bool, placements []placement) {
+	if len(placements) == 0 {
+		return
+	}
+	var counts [board.Size][board.Size]int
+	for _, ship := range placements {
+		for _, p := range ship.cells[:ship.length] {
+			counts[p.X][p.Y]++
+		}
+	}
+	for x := range counts {
+		for y := range counts[x] {
+			if counts[x][y] == len(placements) {
+				marks[x][y] = true
+			}
+		}
+	}
+}
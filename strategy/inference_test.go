@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// waterExcept returns a board of misses with the given cells left unknown.
+func waterExcept(coords ...string) board.Board {
+	var b board.Board
+	for x := 0; x < board.Size; x++ {
+		for y := 0; y < board.Size; y++ {
+			b.Set(board.Point{X: x, Y: y}, board.Water)
+		}
+	}
+	for _, c := range coords {
+		p, _ := board.ParsePoint(c)
+		b.Set(p, board.Unknown)
+	}
+	return b
+}
+
+func points(coords ...string) []board.Point {
+	var res []board.Point
+	for _, c := range coords {
+		p, _ := board.ParsePoint(c)
+		res = append(res, p)
+	}
+	return res
+}
+
+func TestInfer(t *testing.T) {
+	var hitInCorner board.Board
+	hitInCorner.Record(board.Point{X: 0, Y: 0}, "hit")
+	hitInCorner.Record(board.Point{X: 1, Y: 0}, "miss")
+
+	var sunkInCorner board.Board
+	sunkInCorner.Record(board.Point{X: 0, Y: 0}, "sunk")
+
+	tests := []struct {
+		name           string
+		board          board.Board
+		fleet          board.Fleet
+		wantPlacements map[int]int
+		wantCertain    []board.Point
+		wantImpossible []board.Point
+	}{
+		{
+			name:           "empty board",
+			fleet:          board.Fleet{1: 4, 2: 3},
+			wantPlacements: map[int]int{1: 100, 2: 180},
+		},
+		{
+			name:        "hit with one way out",
+			board:       hitInCorner,
+			fleet:       board.StandardFleet(),
+			wantCertain: points("A2"),
+		},
+		{
+			name:           "single spot for the last ship",
+			board:          waterExcept("A1", "B1", "C1", "D1", "J10"),
+			fleet:          board.Fleet{4: 1},
+			wantPlacements: map[int]int{4: 1},
+			wantCertain:    points("A1", "B1", "C1", "D1"),
+			wantImpossible: points("J10"),
+		},
+		{
+			name:           "no single masts left",
+			board:          waterExcept("A1", "C1", "D1"),
+			fleet:          board.Fleet{2: 1},
+			wantPlacements: map[int]int{2: 1},
+			wantCertain:    points("C1", "D1"),
+			wantImpossible: points("A1"),
+		},
+		{
+			name:           "excluded cells are not reported",
+			board:          sunkInCorner,
+			fleet:          board.Fleet{1: 3},
+			wantPlacements: map[int]int{1: 96},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inf := Infer(State{Board: tt.board, Fleet: tt.fleet})
+			if !reflect.DeepEqual(inf.Certain, tt.wantCertain) {
+				t.Errorf("certain = %v, want %v", inf.Certain, tt.wantCertain)
+			}
+			if !reflect.DeepEqual(inf.Impossible, tt.wantImpossible) {
+				t.Errorf("impossible = %v, want %v", inf.Impossible, tt.wantImpossible)
+			}
+			for length, want := range tt.wantPlacements {
+				if inf.Placements[length] != want {
+					t.Errorf("%d placements of length %d, want %d", inf.Placements[length], length, want)
+				}
+			}
+		})
+	}
+}
+
+// TestInferIsSound fires at random fleets and checks every inferred cell
+// against the real layout.
+func TestInferIsSound(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		layout := board.RandomLayout(r)
+		ships := layout.Board()
+		state := State{Fleet: board.StandardFleet()}
+
+		for shot := 0; shot < 60 && !layout.Destroyed(); shot++ {
+			cells := state.Board.Find(board.Unknown)
+			p := cells[r.Intn(len(cells))]
+			result, _ := layout.Fire(p)
+			if ship := state.Board.Record(p, result); ship != nil {
+				state.Fleet[len(ship)]--
+			}
+
+			inf := Infer(state)
+			for _, c := range inf.Certain {
+				if ships.At(c) != board.Occupied {
+					t.Fatalf("seed %d, shot %d: %v is certain but holds no ship\n%s", seed, shot, c, state.Board.String())
+				}
+			}
+			for _, c := range inf.Impossible {
+				if ships.At(c) == board.Occupied {
+					t.Fatalf("seed %d, shot %d: %v is impossible but holds a ship\n%s", seed, shot, c, state.Board.String())
+				}
+			}
+		}
+	}
+}
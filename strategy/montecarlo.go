@@ -0,0 +1,287 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+	"time"
+)
+
+const (
+	monteCarloSamples = 2000
+	monteCarloBudget  = 200 * time.Millisecond
+	// rejected layouts allowed per requested sample before giving up
+	monteCarloAttempts = 50
+)
+
+type monteCarlo struct {
+	r       *rand.Rand
+	samples int
+	budget  time.Duration
+}
+
+type placement struct {
+	length int
+	cells  [4]board.Point
+}
+
+type sampler struct {
+	r          *rand.Rand
+	fleet      [5]int
+	open       []board.Point
+	isOpen     [board.Size][board.Size]bool
+	byLength   [5][]placement
+	covering   [board.Size][board.Size][]placement
+	blocked    [board.Size][board.Size]bool
+	covered    [board.Size][board.Size]bool
+	nCovered   int
+	candidates []placement
+}
+
+func init() {
+	Register("monte-carlo", func(r *rand.Rand) Strategy {
+		return &monteCarlo{r: r, samples: monteCarloSamples, budget: monteCarloBudget}
+	})
+}
+
+func (s *monteCarlo) Recommend(state State) Recommendation {
+	var deadline time.Time
+	if !state.Deadline.IsZero() {
+		deadline = time.Now().Add(s.budget)
+		if state.Deadline.Before(deadline) {
+			deadline = state.Deadline
+		}
+	}
+
+	counts, n := s.sample(state, deadline)
+	if n == 0 {
+		rec := huntTarget{}.Recommend(state)
+		rec.Rule += ", as no fleet layout could be sampled"
+		return rec
+	}
+
+	total := sum(counts)
+	var best board.Point
+	max := -1
+	for _, p := range state.Board.Find(board.Unknown) {
+		if total[p.X][p.Y] > max {
+			max = total[p.X][p.Y]
+			best = p
+		}
+	}
+
+	rec := newRecommendation(state, best, "the cell with a ship in most of the sampled fleet layouts")
+	rec.Scores = ScoreLayouts
+	rec.Score = total[best.X][best.Y]
+	rec.Layouts = n
+	rec.Placements = share(counts, best)
+	rec.RunnersUp = rank(state.Board.Find(board.Unknown), best, func(c board.Point) int { return total[c.X][c.Y] })
+	return rec
+}
+
+// sample counts for every ship length the sampled layouts with a ship of
+// that length on each unknown cell.
+func (s *monteCarlo) sample(state State, deadline time.Time) ([5][board.Size][board.Size]int, int) {
+	var counts [5][board.Size][board.Size]int
+	sm := newSampler(state, s.r)
+
+	n := 0
+	for attempt := 0; n < s.samples && attempt < s.samples*monteCarloAttempts; attempt++ {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		ships, ok := sm.sample()
+		if !ok {
+			continue
+		}
+		for _, ship := range ships {
+			for _, p := range ship.cells[:ship.length] {
+				if state.Board.At(p) == board.Unknown {
+					counts[ship.length][p.X][p.Y]++
+				}
+			}
+		}
+		n++
+	}
+	return counts, n
+}
+
+func newSampler(state State, r *rand.Rand) *sampler {
+	sm := &sampler{
+		r:    r,
+		open: state.Board.Find(board.Hit),
+	}
+	for _, p := range sm.open {
+		sm.isOpen[p.X][p.Y] = true
+	}
+
+	// a fixed order of the placements keeps the samples reproducible
+	for length := 1; length <= 4; length++ {
+		count := state.Fleet[length]
+		if count <= 0 {
+			continue
+		}
+		sm.fleet[length] = count
+		for x := 0; x < board.Size; x++ {
+			for y := 0; y < board.Size; y++ {
+				for _, shape := range board.Shapes[length] {
+					ship, ok := staticPlacement(state.Board, sm.isOpen, shape, board.Point{X: x, Y: y})
+					if !ok {
+						continue
+					}
+					sm.byLength[length] = append(sm.byLength[length], ship)
+					for _, p := range ship.cells[:length] {
+						if sm.isOpen[p.X][p.Y] {
+							sm.covering[p.X][p.Y] = append(sm.covering[p.X][p.Y], ship)
+						}
+					}
+				}
+			}
+		}
+	}
+	return sm
+}
+
+func staticPlacement(b board.Board, isOpen [board.Size][board.Size]bool, shape []board.Point, origin board.Point) (placement, bool) {
+	ship := placement{length: len(shape)}
+	unknown := false
+	for i, offset := range shape {
+		p := board.Point{X: origin.X + offset.X, Y: origin.Y + offset.Y}
+		if !p.InBounds() {
+			return ship, false
+		}
+		switch {
+		case b.At(p) == board.Unknown:
+			unknown = true
+		case !isOpen[p.X][p.Y]:
+			return ship, false
+		}
+		ship.cells[i] = p
+	}
+	if !unknown {
+		return ship, false
+	}
+
+	for _, p := range ship.cells[:ship.length] {
+		for _, n := range p.Surrounding() {
+			if c := b.At(n); (c == board.Hit || c == board.Sunk) && !ship.contains(n) {
+				return ship, false
+			}
+		}
+	}
+	return ship, true
+}
+
+func (sm *sampler) sample() ([]placement, bool) {
+	sm.blocked = [board.Size][board.Size]bool{}
+	sm.covered = [board.Size][board.Size]bool{}
+	sm.nCovered = 0
+	remaining := sm.fleet
+	var ships []placement
+
+	for _, h := range sm.open {
+		if sm.covered[h.X][h.Y] {
+			continue
+		}
+
+		sm.candidates = sm.candidates[:0]
+		for _, ship := range sm.covering[h.X][h.Y] {
+			if remaining[ship.length] > 0 && sm.free(ship) {
+				sm.candidates = append(sm.candidates, ship)
+			}
+		}
+		if len(sm.candidates) == 0 {
+			return nil, false
+		}
+
+		ship := sm.candidates[sm.r.Intn(len(sm.candidates))]
+		sm.place(ship)
+		ships = append(ships, ship)
+		remaining[ship.length]--
+	}
+
+	for length := 4; length >= 1; length-- {
+		for i := 0; i < remaining[length]; i++ {
+			ship, ok := sm.pick(sm.byLength[length])
+			if !ok {
+				return nil, false
+			}
+			sm.place(ship)
+			ships = append(ships, ship)
+		}
+	}
+
+	return ships, sm.nCovered == len(sm.open)
+}
+
+func (sm *sampler) pick(placements []placement) (placement, bool) {
+	if len(placements) == 0 {
+		return placement{}, false
+	}
+	for attempt := 0; attempt < 50; attempt++ {
+		if ship := placements[sm.r.Intn(len(placements))]; sm.free(ship) {
+			return ship, true
+		}
+	}
+
+	sm.candidates = sm.candidates[:0]
+	for _, ship := range placements {
+		if sm.free(ship) {
+			sm.candidates = append(sm.candidates, ship)
+		}
+	}
+	if len(sm.candidates) == 0 {
+		return placement{}, false
+	}
+	return sm.candidates[sm.r.Intn(len(sm.candidates))], true
+}
+
+func (sm *sampler) free(ship placement) bool {
+	for _, p := range ship.cells[:ship.length] {
+		if sm.blocked[p.X][p.Y] {
+			return false
+		}
+	}
+	return true
+}
+
+func (sm *sampler) place(ship placement) {
+	for _, p := range ship.cells[:ship.length] {
+		if sm.isOpen[p.X][p.Y] && !sm.covered[p.X][p.Y] {
+			sm.covered[p.X][p.Y] = true
+			sm.nCovered++
+		}
+		sm.blocked[p.X][p.Y] = true
+		for _, n := range p.Surrounding() {
+			sm.blocked[n.X][n.Y] = true
+		}
+	}
+}
+
+func (p placement) contains(c board.Point) bool {
+	for _, cell := range p.cells[:p.length] {
+		if cell == c {
+			return true
+		}
+	}
+	return false
+}
+
+// HitProbabilities estimates for every unknown cell the chance that it holds
+// a ship, from up to n random fleet layouts consistent with state, stopping
+// at its deadline if it has one. It also returns the number of layouts found,
+// all the estimates are zero if none was.
+func HitProbabilities(state State, n int, r *rand.Rand) ([board.Size][board.Size]float64, int) {
+	var probs [board.Size][board.Size]float64
+	s := &monteCarlo{r: r, samples: n}
+	byLength, found := s.sample(state, state.Deadline)
+	if found == 0 {
+		return probs, 0
+	}
+	counts := sum(byLength)
+	for x := range counts {
+		for y := range counts[x] {
+			probs[x][y] = float64(counts[x][y]) / float64(found)
+		}
+	}
+	return probs, found
+}
@@ -0,0 +1,52 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+)
+
+type random struct {
+	r *rand.Rand
+}
+
+type checkerboard struct {
+	r *rand.Rand
+}
+
+func init() {
+	Register("random", func(r *rand.Rand) Strategy { return random{r} })
+	Register("checkerboard", func(r *rand.Rand) Strategy { return checkerboard{r} })
+}
+
+func (s random) Recommend(state State) Recommendation {
+	return randomCell(state, s.r, state.Board.Find(board.Unknown), "a random unknown cell")
+}
+
+func (s checkerboard) Recommend(state State) Recommendation {
+	if cells := unknownTargets(state); len(cells) > 0 {
+		rec := newRecommendation(state, cells[0], "the first unknown cell next to a hit")
+		rec.RunnersUp = inOrder(cells)
+		return rec
+	}
+
+	cells := state.Board.Find(board.Unknown)
+	var even []board.Point
+	for _, p := range cells {
+		if (p.X+p.Y)%2 == 0 {
+			even = append(even, p)
+		}
+	}
+	if len(even) > 0 {
+		cells = even
+	}
+	return randomCell(state, s.r, cells, "a random unknown cell of the checkerboard pattern")
+}
+
+// randomCell recommends one of cells at random, or A1 when there is no cell
+// left to fire at.
+func randomCell(state State, r *rand.Rand, cells []board.Point, rule string) Recommendation {
+	if len(cells) == 0 {
+		return newRecommendation(state, board.Point{}, "no unknown cell is left")
+	}
+	return newRecommendation(state, cells[r.Intn(len(cells))], rule)
+}
@@ -0,0 +1,127 @@
+package strategy
+
+import (
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const Default = "hunt-target"
+
+type Shot struct {
+	Point  board.Point
+	Result string
+}
+
+type State struct {
+	Board    board.Board
+	Fleet    board.Fleet
+	Shots    []Shot
+	Deadline time.Time
+}
+
+type Strategy interface {
+	Recommend(state State) Recommendation
+}
+
+type Factory func(r *rand.Rand) Strategy
+
+var registry = map[string]Factory{}
+
+func Register(name string, factory Factory) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("strategy %s registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// a strategy that missed its deadline keeps deciding in the background, it is
+// not used again until it is done, so that its state is never shared
+var (
+	pendingMu sync.Mutex
+	pending   = map[Strategy]chan struct{}{}
+)
+
+func Decide(s Strategy, state State) Recommendation {
+	pendingMu.Lock()
+	busy := pending[s]
+	pendingMu.Unlock()
+
+	if state.Deadline.IsZero() {
+		if busy != nil {
+			<-busy
+		}
+		return s.Recommend(state)
+	}
+
+	remaining := time.Until(state.Deadline)
+	if remaining <= 0 {
+		log.Warn("strategy [Decide] - no time left, using fallback")
+		return fallback(state)
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	if busy != nil {
+		select {
+		case <-busy:
+		case <-timer.C:
+			log.Warn("strategy [Decide] - still busy with the last turn, using fallback")
+			return fallback(state)
+		}
+	}
+
+	res := make(chan Recommendation, 1)
+	done := make(chan struct{})
+	pendingMu.Lock()
+	pending[s] = done
+	pendingMu.Unlock()
+	go func(state State) {
+		res <- s.Recommend(state)
+		pendingMu.Lock()
+		delete(pending, s)
+		pendingMu.Unlock()
+		close(done)
+	}(state.clone())
+
+	select {
+	case rec := <-res:
+		return rec
+	case <-timer.C:
+		log.Warn("strategy [Decide] - deadline exceeded, using fallback", "budget", remaining)
+		return fallback(state)
+	}
+}
+
+func fallback(state State) Recommendation {
+	rec := huntTarget{}.Recommend(state)
+	rec.Rule += ", as the strategy ran out of time"
+	return rec
+}
+
+func (s State) clone() State {
+	s.Fleet = s.Fleet.Clone()
+	s.Shots = append([]Shot(nil), s.Shots...)
+	return s
+}
+
+func New(name string, r *rand.Rand) (Strategy, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+	return factory(r), nil
+}
+
+func Names() []string {
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
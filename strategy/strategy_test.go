@@ -0,0 +1,62 @@
+package strategy
+
+import (
+	"github.com/wojtekolesinski/battleships/board"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type slow struct {
+	r     *rand.Rand
+	delay time.Duration
+}
+
+func (s *slow) Recommend(state State) Recommendation {
+	time.Sleep(s.delay)
+	cells := state.Board.Find(board.Unknown)
+	return Recommendation{Point: cells[s.r.Intn(len(cells))]}
+}
+
+func TestDecideFallsBackWithoutSharingTheStrategy(t *testing.T) {
+	s := &slow{r: rand.New(rand.NewSource(1)), delay: 50 * time.Millisecond}
+	state := State{Fleet: board.StandardFleet()}
+
+	for i := 0; i < 3; i++ {
+		state.Deadline = time.Now().Add(10 * time.Millisecond)
+		p := Decide(s, state).Point
+		if !p.InBounds() {
+			t.Fatalf("Decide returned %v", p)
+		}
+	}
+
+	state.Deadline = time.Time{}
+	if p := Decide(s, state).Point; !p.InBounds() {
+		t.Fatalf("Decide returned %v", p)
+	}
+}
+
+func TestDecideUsesTheStrategyInTime(t *testing.T) {
+	s := &slow{r: rand.New(rand.NewSource(1))}
+	var b board.Board
+	b.Record(board.Point{X: 0, Y: 0}, "miss")
+	state := State{Board: b, Fleet: board.StandardFleet(), Deadline: time.Now().Add(time.Second)}
+
+	want := (&slow{r: rand.New(rand.NewSource(1))}).Recommend(state).Point
+	if got := Decide(s, state).Point; got != want {
+		t.Errorf("Decide = %v, want %v", got, want)
+	}
+}
+
+func TestRecommendWithoutUnknownCells(t *testing.T) {
+	state := State{Board: waterExcept(), Fleet: board.Fleet{}}
+	for _, name := range Names() {
+		s, err := New(name, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := s.Recommend(state).Point; !p.InBounds() {
+			t.Errorf("%s recommended %v", name, p)
+		}
+	}
+}